@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/cardinfo"
+	"ygocdb-tui/internal/format"
+	"ygocdb-tui/internal/log"
+	"ygocdb-tui/internal/query"
+	"ygocdb-tui/internal/ui"
+)
+
+// runSearch runs the search command, printing the cards matching a query in
+// the output format, or as text if it is nil, and returns the exit status
+func runSearch(source api.CardSource, opts ui.Options, output *format.Format, args []string) int {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	limit := fs.Int("limit", 50, "maximum number of cards to print (0 for all)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] search [-limit n] <query>\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Print the cards matching a query, which may use the query syntax of the TUI.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	input := strings.Join(fs.Args(), " ")
+	if strings.TrimSpace(input) == "" {
+		fs.Usage()
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cards, err := searchCards(ctx, source, input, opts.Region, *limit)
+	if err != nil {
+		log.Error("search failed: %v", err)
+		fmt.Fprintf(os.Stderr, "错误: %s\n", ui.DescribeError(err))
+		return 1
+	}
+	if len(cards) == 0 {
+		fmt.Fprintln(os.Stderr, "未找到相关卡片")
+		return 1
+	}
+	if err := printCards(cards, opts, output); err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// printCards prints a summary line per card, or the cards in the output
+// format if it is not nil
+func printCards(cards []api.Card, opts ui.Options, output *format.Format) error {
+	if output != nil {
+		return output.Write(os.Stdout, cards, opts.NameLang)
+	}
+	for _, card := range cards {
+		fmt.Println(ui.FormatCardSummary(card, opts.NameLang, opts.Banlist))
+	}
+	return nil
+}
+
+// searchCards returns up to limit cards matching a query in the region.
+// Bare passcodes are looked up directly, as in the TUI.
+func searchCards(ctx context.Context, source api.CardSource, input string, region cardinfo.Region, limit int) ([]api.Card, error) {
+	q, err := query.Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	if id, ok := q.CardID(); ok {
+		card, err := source.GetCardByIDContext(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		return []api.Card{card.Card}, nil
+	}
+	match := func(card *api.Card) bool {
+		return region.Allows(cardinfo.OT(card.Data.OT)) && (q.Filter == nil || q.Filter.Match(card))
+	}
+
+	var cards []api.Card
+	if q.Search == "" {
+		// Only local sources can list cards by card data alone
+		filterSource, ok := source.(api.FilterSource)
+		if !ok {
+			return nil, errors.New("在线搜索需要至少一个关键词，例如: 龙 attr:暗 level>=8")
+		}
+		if cards, err = filterSource.FilterCards(ctx, match); err != nil {
+			return nil, err
+		}
+	} else {
+		for start := 0; ; {
+			results, err := source.SearchCardsContext(ctx, q.Search, start)
+			if err != nil {
+				return nil, err
+			}
+			for _, card := range results.Result {
+				if match(&card) {
+					cards = append(cards, card)
+				}
+			}
+			if results.Next <= 0 || (limit > 0 && len(cards) >= limit) {
+				break
+			}
+			start = results.Next
+		}
+	}
+	if limit > 0 && len(cards) > limit {
+		cards = cards[:limit]
+	}
+	return cards, nil
+}
+
+// runShow runs the show command, printing the details of cards by passcode,
+// or the cards in the output format if it is not nil, and returns the exit
+// status
+func runShow(source api.CardSource, opts ui.Options, output *format.Format, args []string) int {
+	fs := flag.NewFlagSet("show", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] show <id>...\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Print the details of cards by passcode.\n")
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	status := 0
+	var cards []api.Card
+	for _, arg := range fs.Args() {
+		card, err := getCard(ctx, source, arg)
+		if err != nil {
+			log.Error("failed to show card %s: %v", arg, err)
+			fmt.Fprintf(os.Stderr, "%s: %s\n", arg, ui.DescribeError(err))
+			status = 1
+			continue
+		}
+		if output != nil {
+			cards = append(cards, card.Card)
+			continue
+		}
+		if len(cards) > 0 {
+			fmt.Println()
+		}
+		fmt.Println(ui.FormatCardDetails(*card, opts.NameLang, opts.Banlist))
+		cards = append(cards, card.Card)
+	}
+	if output != nil {
+		if err := output.Write(os.Stdout, cards, opts.NameLang); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			return 1
+		}
+	}
+	return status
+}
+
+// runLookup runs the lookup command, printing a summary of each card given
+// by passcode as an argument or, for "-", on stdin, or the cards in the
+// output format if it is not nil, and returns the exit status
+func runLookup(source api.CardSource, opts ui.Options, output *format.Format, args []string) int {
+	fs := flag.NewFlagSet("lookup", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] lookup <id>... | -\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Print a line per card given by passcode. With \"-\", passcodes are read from\n")
+		fmt.Fprintf(os.Stderr, "stdin, separated by whitespace or commas.\n")
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	ids := fs.Args()
+	if len(ids) == 1 && ids[0] == "-" {
+		var err error
+		if ids, err = readIDs(os.Stdin); err != nil {
+			fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+			return 1
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	status := 0
+	var cards []api.Card
+	for _, arg := range ids {
+		card, err := getCard(ctx, source, arg)
+		if err != nil {
+			log.Error("failed to look up card %s: %v", arg, err)
+			fmt.Fprintf(os.Stderr, "%s: %s\n", arg, ui.DescribeError(err))
+			status = 1
+			if errors.Is(err, context.Canceled) {
+				break
+			}
+			continue
+		}
+		cards = append(cards, card.Card)
+	}
+	if err := printCards(cards, opts, output); err != nil {
+		fmt.Fprintf(os.Stderr, "错误: %v\n", err)
+		return 1
+	}
+	return status
+}
+
+// readIDs reads passcodes separated by whitespace or commas
+func readIDs(r io.Reader) ([]string, error) {
+	var ids []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		ids = append(ids, strings.FieldsFunc(scanner.Text(), func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})...)
+	}
+	return ids, scanner.Err()
+}
+
+// getCard looks up a card by a passcode argument
+func getCard(ctx context.Context, source api.CardSource, arg string) (*api.GetCardResponse, error) {
+	id, err := strconv.Atoi(strings.TrimSpace(arg))
+	if err != nil || id <= 0 {
+		return nil, fmt.Errorf("无效的卡片密码: %s", arg)
+	}
+	return source.GetCardByIDContext(ctx, id)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"ygocdb-tui/internal/config"
+	"ygocdb-tui/internal/log"
+)
+
+// loadConfig loads the config file at path, or at the default path if path
+// is empty. Only a config file given explicitly must exist.
+func loadConfig(path string) (*config.Config, error) {
+	if path == "" {
+		var err error
+		if path, err = config.Path(); err != nil {
+			log.Warn("config file disabled: %v", err)
+			return &config.Config{}, nil
+		}
+		return config.Load(path)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return config.Load(path)
+}
+
+// applyConfig sets the flags of fs that were not given on the command line
+// to the settings of the config file
+func applyConfig(fs *flag.FlagSet, cfg *config.Config) error {
+	given := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		given[f.Name] = true
+	})
+	
+	var err error
+	set := func(name string, values ...string) {
+		if given[name] || err != nil {
+			return
+		}
+		for _, value := range values {
+			if value == "" {
+				continue
+			}
+			if setErr := fs.Set(name, value); setErr != nil {
+				err = fmt.Errorf("invalid config setting for -%s: %w", name, setErr)
+				return
+			}
+		}
+	}
+	
+	set("base-url", cfg.BaseURL)
+	if cfg.Timeout != 0 {
+		set("timeout", cfg.Timeout.String())
+	}
+	if cfg.Retries != nil {
+		set("retries", strconv.Itoa(*cfg.Retries))
+	}
+	if cfg.CacheTTL != 0 {
+		set("cache-ttl", cfg.CacheTTL.String())
+	}
+	set("region", cfg.Region)
+	set("lang", cfg.Lang)
+	set("format", cfg.Format)
+	set("cdb", cfg.CDB...)
+	set("strings", cfg.Strings...)
+	set("lflist", cfg.LFList...)
+	set("banlist", cfg.Banlist)
+	set("log-level", cfg.LogLevel)
+	set("log-dir", cfg.LogDir)
+	set("dataset-dir", cfg.DatasetDir)
+	if cfg.PageSize != 0 {
+		set("page-size", strconv.Itoa(cfg.PageSize))
+	}
+	return err
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"reflect"
+	"testing"
+	"time"
+	"ygocdb-tui/internal/config"
+)
+
+func TestApplyConfig(t *testing.T) {
+	fs := flag.NewFlagSet("ygocdb-tui", flag.ContinueOnError)
+	baseURL := fs.String("base-url", "https://ygocdb.com/api/v0", "")
+	timeout := fs.Duration("timeout", 10*time.Second, "")
+	retries := fs.Int("retries", 3, "")
+	region := fs.String("region", "all", "")
+	pageSize := fs.Int("page-size", 20, "")
+	var cdbPaths stringList
+	fs.Var(&cdbPaths, "cdb", "")
+	if err := fs.Parse([]string{"-region", "tcg", "-cdb", "/given.cdb"}); err != nil {
+		t.Fatal(err)
+	}
+
+	zero := 0
+	cfg := &config.Config{
+		BaseURL: "https://example.com/api",
+		Timeout: 5 * time.Second,
+		Retries: &zero,
+		Region:  "ocg",
+		CDB:     []string{"/a.cdb", "/b.cdb"},
+	}
+	if err := applyConfig(fs, cfg); err != nil {
+		t.Fatalf("applyConfig() error: %v", err)
+	}
+
+	// Flags given on the command line override the config file
+	if *region != "tcg" {
+		t.Errorf("-region = %q, want the command line value", *region)
+	}
+	if want := (stringList{"/given.cdb"}); !reflect.DeepEqual(cdbPaths, want) {
+		t.Errorf("-cdb = %q, want %q", cdbPaths, want)
+	}
+	// Other flags are set from the config file
+	if *baseURL != cfg.BaseURL || *timeout != cfg.Timeout || *retries != 0 {
+		t.Errorf("-base-url, -timeout, -retries = %q, %s, %d, want the config values", *baseURL, *timeout, *retries)
+	}
+	// Settings that are not given keep the flag defaults
+	if *pageSize != 20 {
+		t.Errorf("-page-size = %d, want the default", *pageSize)
+	}
+}
+
+func TestApplyConfigRepeatedFlag(t *testing.T) {
+	fs := flag.NewFlagSet("ygocdb-tui", flag.ContinueOnError)
+	var cdbPaths stringList
+	fs.Var(&cdbPaths, "cdb", "")
+	if err := fs.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyConfig(fs, &config.Config{CDB: []string{"/a.cdb", "", "/b.cdb"}}); err != nil {
+		t.Fatalf("applyConfig() error: %v", err)
+	}
+	if want := (stringList{"/a.cdb", "/b.cdb"}); !reflect.DeepEqual(cdbPaths, want) {
+		t.Errorf("-cdb = %q, want %q", cdbPaths, want)
+	}
+}
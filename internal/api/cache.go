@@ -0,0 +1,91 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"ygocdb-tui/internal/log"
+	"ygocdb-tui/internal/paths"
+)
+
+const (
+	// DefaultCacheTTL is the default time a cached response is served without
+	// revalidation
+	DefaultCacheTTL = 24 * time.Hour
+)
+
+// Cache is an on-disk store of API responses. Fresh entries are served
+// directly, expired entries are revalidated with ETag/Last-Modified and
+// served as a fallback when the API cannot be reached.
+type Cache struct {
+	dir string
+	ttl time.Duration
+}
+
+// cacheEntry represents a cached API response
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	StoredAt     time.Time `json:"stored_at"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	Body         []byte    `json:"body"`
+}
+
+// NewCache creates a cache storing responses in dir for ttl
+func NewCache(dir string, ttl time.Duration) (*Cache, error) {
+	log.Debug("Creating response cache in %s with TTL %s", dir, ttl)
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &Cache{dir: dir, ttl: ttl}, nil
+}
+
+// path returns the file path of the entry for apiURL
+func (c *Cache) path(apiURL string) string {
+	sum := sha256.Sum256([]byte(apiURL))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// load returns the cached entry for apiURL, or nil if there is none
+func (c *Cache) load(apiURL string) *cacheEntry {
+	data, err := os.ReadFile(c.path(apiURL))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn("Failed to read cache entry for %s: %v", apiURL, err)
+		}
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != apiURL {
+		log.Warn("Ignoring corrupt cache entry for %s", apiURL)
+		return nil
+	}
+	return &entry
+}
+
+// store writes the entry to disk
+func (c *Cache) store(entry *cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		log.Warn("Failed to encode cache entry for %s: %v", entry.URL, err)
+		return
+	}
+
+	// Write atomically so concurrent readers never see a partial entry
+	if err := paths.WriteFile(c.dir, filepath.Base(c.path(entry.URL)), data); err != nil {
+		log.Warn("Failed to write cache entry for %s: %v", entry.URL, err)
+		return
+	}
+	log.Debug("Cached response for %s", entry.URL)
+}
+
+// fresh reports whether the entry can be served without revalidation
+func (c *Cache) fresh(entry *cacheEntry, now time.Time) bool {
+	return now.Sub(entry.StoredAt) < c.ttl
+}
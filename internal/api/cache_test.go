@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// cardServer serves a card with validators, answering matching conditional
+// requests with 304 Not Modified. status overrides the response if set.
+type cardServer struct {
+	*httptest.Server
+	etag         string
+	lastModified string
+	status       atomic.Int32
+	requests     atomic.Int32
+	conditional  atomic.Int32
+}
+
+// newCardServer starts a cardServer
+func newCardServer(t *testing.T, etag, lastModified string) *cardServer {
+	s := &cardServer{etag: etag, lastModified: lastModified}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.requests.Add(1)
+		if status := int(s.status.Load()); status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		if r.Header.Get("If-None-Match") != "" || r.Header.Get("If-Modified-Since") != "" {
+			s.conditional.Add(1)
+			if (s.etag != "" && r.Header.Get("If-None-Match") == s.etag) ||
+				(s.lastModified != "" && r.Header.Get("If-Modified-Since") == s.lastModified) {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+		if s.etag != "" {
+			w.Header().Set("ETag", s.etag)
+		}
+		if s.lastModified != "" {
+			w.Header().Set("Last-Modified", s.lastModified)
+		}
+		w.Write([]byte(`{"id":1,"cn_name":"测试"}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// newCachedClient creates a client of the server caching responses for ttl
+func newCachedClient(t *testing.T, url string, ttl time.Duration) *Client {
+	cache, err := NewCache(t.TempDir(), ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewClient(WithBaseURL(url), WithRetry(1, 0), WithCache(cache))
+}
+
+// getCard gets card 1 and checks that it is served
+func getCard(t *testing.T, c *Client) {
+	t.Helper()
+	card, err := c.GetCardByIDContext(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetCardByIDContext() error: %v", err)
+	}
+	if card.CnName != "测试" {
+		t.Fatalf("GetCardByIDContext() = %+v", card)
+	}
+}
+
+func TestCacheServesFreshResponses(t *testing.T) {
+	s := newCardServer(t, `"v1"`, "")
+	c := newCachedClient(t, s.URL, time.Hour)
+	getCard(t, c)
+	getCard(t, c)
+	if got := s.requests.Load(); got != 1 {
+		t.Errorf("made %d requests, want 1", got)
+	}
+}
+
+func TestCacheExpires(t *testing.T) {
+	s := newCardServer(t, "", "")
+	c := newCachedClient(t, s.URL, time.Hour)
+	getCard(t, c)
+
+	// Age the entry beyond the TTL
+	url := s.URL + "/api/v0/card/1"
+	entry := c.cache.load(url)
+	if entry == nil {
+		t.Fatal("response was not cached")
+	}
+	entry.StoredAt = entry.StoredAt.Add(-2 * time.Hour)
+	c.cache.store(entry)
+
+	getCard(t, c)
+	if got := s.requests.Load(); got != 2 {
+		t.Errorf("made %d requests, want 2", got)
+	}
+	if entry := c.cache.load(url); time.Since(entry.StoredAt) > time.Minute {
+		t.Errorf("refreshed entry stored at %s", entry.StoredAt)
+	}
+}
+
+func TestCacheRevalidates(t *testing.T) {
+	tests := []struct {
+		name         string
+		etag         string
+		lastModified string
+	}{
+		{"ETag", `"v1"`, ""},
+		{"Last-Modified", "", "Mon, 01 Apr 2024 12:00:00 GMT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newCardServer(t, tt.etag, tt.lastModified)
+			c := newCachedClient(t, s.URL, 0)
+			getCard(t, c)
+			getCard(t, c)
+			getCard(t, c)
+			if got := s.requests.Load(); got != 3 {
+				t.Errorf("made %d requests, want 3", got)
+			}
+			if got := s.conditional.Load(); got != 2 {
+				t.Errorf("made %d conditional requests, want 2", got)
+			}
+		})
+	}
+}
+
+func TestCacheServesStaleResponsesOnError(t *testing.T) {
+	for _, status := range []int{http.StatusInternalServerError, http.StatusTooManyRequests} {
+		s := newCardServer(t, `"v1"`, "")
+		c := newCachedClient(t, s.URL, 0)
+		getCard(t, c)
+		s.status.Store(int32(status))
+		getCard(t, c)
+	}
+
+	// A stale response is not served for cards that no longer exist
+	s := newCardServer(t, `"v1"`, "")
+	c := newCachedClient(t, s.URL, 0)
+	getCard(t, c)
+	s.status.Store(http.StatusNotFound)
+	if _, err := c.GetCardByIDContext(context.Background(), 1); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetCardByIDContext() error = %v, want ErrNotFound", err)
+	}
+
+	// Without a cached response the error is returned
+	s = newCardServer(t, "", "")
+	s.status.Store(http.StatusInternalServerError)
+	c = newCachedClient(t, s.URL, 0)
+	if _, err := c.GetCardByIDContext(context.Background(), 1); !errors.Is(err, ErrServer) {
+		t.Errorf("GetCardByIDContext() error = %v, want ErrServer", err)
+	}
+}
+
+func TestCacheSkipsEmptyAndInvalidResponses(t *testing.T) {
+	for _, body := range []string{"{}", "null", "<html>"} {
+		s := newTestServer(t, testResponse{status: 200, body: body}, testResponse{status: 200, body: `{"id":1,"cn_name":"测试"}`})
+		c := newCachedClient(t, s.URL, time.Hour)
+		if _, err := c.GetCardByIDContext(context.Background(), 1); err == nil {
+			t.Errorf("GetCardByIDContext() of %s succeeded", body)
+		}
+		getCard(t, c)
+		if got := s.requests.Load(); got != 2 {
+			t.Errorf("after %s made %d requests, want 2", body, got)
+		}
+	}
+}
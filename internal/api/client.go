@@ -1,40 +1,100 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 	"ygocdb-tui/internal/log"
 )
 
 const (
 	// BaseURL is the base URL for the ygocdb API
 	BaseURL = "https://ygocdb.com"
+	// DefaultTimeout is the default timeout for a single API request
+	DefaultTimeout = 10 * time.Second
+	// DefaultMaxRetries is the default number of retries for transient failures
+	DefaultMaxRetries = 3
+	// DefaultRetryDelay is the default base delay of the exponential backoff
+	DefaultRetryDelay = 500 * time.Millisecond
+	// maxRetryDelay caps the backoff delay and the honoured Retry-After delay
+	maxRetryDelay = 30 * time.Second
 )
 
 // Client represents the API client
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
+	maxRetries int
+	retryDelay time.Duration
+	cache      *Cache
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithBaseURL sets the base URL of the API
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithTimeout sets the timeout of a single API request
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithRetry sets the number of retries for transient failures and the base
+// delay of the exponential backoff between them
+func WithRetry(maxRetries int, baseDelay time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.retryDelay = baseDelay
+	}
+}
+
+// WithCache stores responses in cache and serves them from there
+func WithCache(cache *Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
 }
 
 // NewClient creates a new API client
-func NewClient() *Client {
+func NewClient(opts ...Option) *Client {
 	log.Debug("Creating new API client")
 	
 	client := &Client{
 		baseURL:    BaseURL,
-		httpClient: &http.Client{},
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		maxRetries: DefaultMaxRetries,
+		retryDelay: DefaultRetryDelay,
+	}
+	for _, opt := range opts {
+		opt(client)
 	}
 	
-	log.Debug("API client created with baseURL: %s", client.baseURL)
+	log.Debug("API client created with baseURL: %s, timeout: %s", client.baseURL, client.httpClient.Timeout)
 	return client
 }
 
 // SearchCards searches for cards by query with pagination
 func (c *Client) SearchCards(query string, start int) (*SearchResponse, error) {
+	return c.SearchCardsContext(context.Background(), query, start)
+}
+
+// SearchCardsContext searches for cards by query with pagination, aborting
+// the request when ctx is cancelled
+func (c *Client) SearchCardsContext(ctx context.Context, query string, start int) (*SearchResponse, error) {
 	log.Info("Searching cards with query: %s, start: %d", query, start)
 	
 	// URL encode the query
@@ -45,39 +105,17 @@ func (c *Client) SearchCards(query string, start int) (*SearchResponse, error) {
 	apiURL := fmt.Sprintf("%s/api/v0/?search=%s&start=%d", c.baseURL, encodedQuery, start)
 	log.Debug("API URL: %s", apiURL)
 
-	// Make the request
-	log.Debug("Making HTTP request to API")
-	resp, err := c.httpClient.Get(apiURL)
+	body, err := c.get(ctx, apiURL)
 	if err != nil {
-		log.Error("Failed to make HTTP request: %v", err)
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-	
-	log.Debug("Received HTTP response with status code: %d", resp.StatusCode)
-
-	// Check status code
-	if resp.StatusCode != http.StatusOK {
-		log.Error("API returned non-OK status code: %d", resp.StatusCode)
-		return nil, fmt.Errorf("API returned status code %d", resp.StatusCode)
-	}
-
-	// Read response body
-	log.Debug("Reading response body")
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Error("Failed to read response body: %v", err)
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-	
-	log.Debug("Response body read, size: %d bytes", len(body))
 
 	// Parse JSON response
 	log.Debug("Parsing JSON response")
 	var searchResp SearchResponse
 	if err := json.Unmarshal(body, &searchResp); err != nil {
 		log.Error("Failed to parse JSON response: %v", err)
-		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrDecode, err)
 	}
 	
 	log.Info("Search completed successfully, found %d results", len(searchResp.Result))
@@ -86,27 +124,193 @@ func (c *Client) SearchCards(query string, start int) (*SearchResponse, error) {
 
 // GetCardByID gets a card by its ID
 func (c *Client) GetCardByID(cardID int) (*GetCardResponse, error) {
+	return c.GetCardByIDContext(context.Background(), cardID)
+}
+
+// GetCardByIDContext gets a card by its ID, aborting the request when ctx is
+// cancelled
+func (c *Client) GetCardByIDContext(ctx context.Context, cardID int) (*GetCardResponse, error) {
 	log.Info("Getting card by ID: %d", cardID)
 	
 	// Construct the URL
 	apiURL := fmt.Sprintf("%s/api/v0/card/%d", c.baseURL, cardID)
 	log.Debug("API URL: %s", apiURL)
 
+	body, err := c.get(ctx, apiURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse JSON response
+	log.Debug("Parsing JSON response")
+	var cardResp GetCardResponse
+	if err := json.Unmarshal(body, &cardResp); err != nil {
+		log.Error("Failed to parse JSON response: %v", err)
+		return nil, fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+	
+	// The API answers unknown IDs with an empty object
+	if cardResp.ID == 0 {
+		log.Warn("Card not found, ID: %d", cardID)
+		return nil, fmt.Errorf("%w: %d", ErrNotFound, cardID)
+	}
+	
+	log.Info("Card retrieval completed successfully, card ID: %d", cardResp.ID)
+	return &cardResp, nil
+}
+
+// Download fetches a file from the API, such as the bulk card dataset. The
+// download is not cached and not bound by the per-request timeout, so use
+// ctx to limit it.
+func (c *Client) Download(ctx context.Context, path string) ([]byte, error) {
+	log.Info("Downloading %s", path)
+	
+	httpClient := *c.httpClient
+	httpClient.Timeout = 0
+	downloader := *c
+	downloader.httpClient = &httpClient
+	downloader.cache = nil
+	
+	body, err := downloader.get(ctx, c.baseURL+path)
+	if err != nil {
+		return nil, err
+	}
+	
+	log.Info("Download completed, size: %d bytes", len(body))
+	return body, nil
+}
+
+// response represents a successful API response
+type response struct {
+	body         []byte
+	etag         string
+	lastModified string
+	notModified  bool
+}
+
+// get returns the response body for apiURL, serving it from the cache when
+// possible
+func (c *Client) get(ctx context.Context, apiURL string) ([]byte, error) {
+	if c.cache == nil {
+		resp, err := c.getWithRetry(ctx, apiURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		return resp.body, nil
+	}
+	
+	now := time.Now()
+	entry := c.cache.load(apiURL)
+	if entry != nil && c.cache.fresh(entry, now) {
+		log.Debug("Serving fresh cached response for %s", apiURL)
+		return entry.Body, nil
+	}
+	
+	resp, err := c.getWithRetry(ctx, apiURL, entry)
+	if err != nil {
+		if entry != nil && isUnavailable(err) {
+			log.Warn("Serving stale cached response for %s: %v", apiURL, err)
+			return entry.Body, nil
+		}
+		return nil, err
+	}
+	
+	if resp.notModified {
+		log.Debug("Cached response for %s revalidated", apiURL)
+		entry.StoredAt = now
+		c.cache.store(entry)
+		return entry.Body, nil
+	}
+	
+	// Only cache well-formed responses so a broken reply is not served for
+	// the whole TTL, and not the empty object of unknown IDs so newly
+	// released cards are found as soon as the API knows them
+	if json.Valid(resp.body) && !isEmptyObject(resp.body) {
+		c.cache.store(&cacheEntry{
+			URL:          apiURL,
+			StoredAt:     now,
+			ETag:         resp.etag,
+			LastModified: resp.lastModified,
+			Body:         resp.body,
+		})
+	}
+	return resp.body, nil
+}
+
+// getWithRetry fetches apiURL, retrying transient failures with jittered
+// exponential backoff
+func (c *Client) getWithRetry(ctx context.Context, apiURL string, cached *cacheEntry) (*response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.fetch(ctx, apiURL, cached)
+		if err == nil {
+			return resp, nil
+		}
+		if attempt >= c.maxRetries || !isTemporary(ctx, err) {
+			return nil, err
+		}
+		
+		delay := c.backoff(attempt)
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			if statusErr.RetryAfter > maxRetryDelay {
+				log.Warn("Retry-After of %s exceeds the maximum delay, giving up", statusErr.RetryAfter)
+				return nil, err
+			}
+			delay = statusErr.RetryAfter
+		}
+		
+		log.Warn("Request failed (attempt %d/%d): %v, retrying in %s", attempt+1, c.maxRetries+1, err, delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// fetch performs a single GET request. When cached is not nil the request is
+// made conditional on the cached validators.
+func (c *Client) fetch(ctx context.Context, apiURL string, cached *cacheEntry) (*response, error) {
 	// Make the request
 	log.Debug("Making HTTP request to API")
-	resp, err := c.httpClient.Get(apiURL)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		log.Error("Failed to create HTTP request: %v", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		log.Error("Failed to make HTTP request: %v", err)
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: %w", ErrNetwork, err)
 	}
 	defer resp.Body.Close()
 	
 	log.Debug("Received HTTP response with status code: %d", resp.StatusCode)
 
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		return &response{notModified: true}, nil
+	}
+
 	// Check status code
 	if resp.StatusCode != http.StatusOK {
 		log.Error("API returned non-OK status code: %d", resp.StatusCode)
-		return nil, fmt.Errorf("API returned status code %d", resp.StatusCode)
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
 
 	// Read response body
@@ -114,19 +318,56 @@ func (c *Client) GetCardByID(cardID int) (*GetCardResponse, error) {
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		log.Error("Failed to read response body: %v", err)
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("%w: failed to read response body: %w", ErrNetwork, err)
 	}
 	
 	log.Debug("Response body read, size: %d bytes", len(body))
+	return &response{
+		body:         body,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}, nil
+}
 
-	// Parse JSON response
-	log.Debug("Parsing JSON response")
-	var cardResp GetCardResponse
-	if err := json.Unmarshal(body, &cardResp); err != nil {
-		log.Error("Failed to parse JSON response: %v", err)
-		return nil, fmt.Errorf("failed to parse JSON response: %w", err)
+// backoff returns the jittered delay before the given retry attempt, or no
+// delay if the base delay is zero
+func (c *Client) backoff(attempt int) time.Duration {
+	if c.retryDelay <= 0 {
+		return 0
 	}
-	
-	log.Info("Card retrieval completed successfully, card ID: %d", cardResp.ID)
-	return &cardResp, nil
-}
\ No newline at end of file
+	delay := c.retryDelay << attempt
+	// A large attempt shifts the delay out of range
+	if attempt >= 63 || delay <= 0 || delay > maxRetryDelay {
+		delay = maxRetryDelay
+	}
+	// Jitter the delay within [delay/2, delay]
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// isEmptyObject reports whether body is an empty JSON object or null
+func isEmptyObject(body []byte) bool {
+	var obj map[string]json.RawMessage
+	return json.Unmarshal(body, &obj) == nil && len(obj) == 0
+}
+
+// isTemporary reports whether err is a transient failure worth retrying
+func isTemporary(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Temporary()
+	}
+	return errors.Is(err, ErrNetwork)
+}
+
+// isUnavailable reports whether err means the API could not serve the
+// request, so that a stale cached response is better than none
+func isUnavailable(err error) bool {
+	return errors.Is(err, ErrNetwork) ||
+		errors.Is(err, ErrServer) ||
+		errors.Is(err, ErrRateLimited) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
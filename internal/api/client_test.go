@@ -0,0 +1,182 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testServer serves the responses in turn, repeating the last one, and
+// counts the requests it receives
+type testServer struct {
+	*httptest.Server
+	requests atomic.Int32
+}
+
+// testResponse is a response of a testServer
+type testResponse struct {
+	status     int
+	body       string
+	retryAfter string
+}
+
+// newTestServer starts a server answering with responses in turn
+func newTestServer(t *testing.T, responses ...testResponse) *testServer {
+	s := &testServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(s.requests.Add(1))
+		resp := responses[min(n, len(responses))-1]
+		if resp.retryAfter != "" {
+			w.Header().Set("Retry-After", resp.retryAfter)
+		}
+		w.WriteHeader(resp.status)
+		w.Write([]byte(resp.body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// newTestClient creates a client of the server retrying without delay
+func newTestClient(s *testServer, opts ...Option) *Client {
+	return NewClient(append([]Option{WithBaseURL(s.URL), WithRetry(2, 0)}, opts...)...)
+}
+
+func TestGetCardErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []testResponse
+		want      error
+		attempts  int
+	}{
+		{"not found", []testResponse{{status: 404}}, ErrNotFound, 1},
+		{"unknown ID", []testResponse{{status: 200, body: "{}"}}, ErrNotFound, 1},
+		{"rate limited", []testResponse{{status: 429}}, ErrRateLimited, 3},
+		{"server error", []testResponse{{status: 500}}, ErrServer, 3},
+		{"bad gateway", []testResponse{{status: 502}}, ErrServer, 3},
+		{"invalid JSON", []testResponse{{status: 200, body: "<html>"}}, ErrDecode, 1},
+		{"Retry-After too long", []testResponse{{status: 503, retryAfter: "3600"}}, ErrServer, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, tt.responses...)
+			_, err := newTestClient(s).GetCardByIDContext(context.Background(), 1)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("GetCardByIDContext() error = %v, want %v", err, tt.want)
+			}
+			if got := int(s.requests.Load()); got != tt.attempts {
+				t.Errorf("made %d attempts, want %d", got, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestGetCardUnexpectedStatus(t *testing.T) {
+	s := newTestServer(t, testResponse{status: 400})
+	_, err := newTestClient(s).GetCardByIDContext(context.Background(), 1)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != 400 {
+		t.Fatalf("GetCardByIDContext() error = %v, want a StatusError with 400", err)
+	}
+	for _, class := range []error{ErrNotFound, ErrRateLimited, ErrServer, ErrNetwork} {
+		if errors.Is(err, class) {
+			t.Errorf("GetCardByIDContext() error is %v", class)
+		}
+	}
+	if got := s.requests.Load(); got != 1 {
+		t.Errorf("made %d attempts, want 1", got)
+	}
+}
+
+func TestGetCardRetries(t *testing.T) {
+	s := newTestServer(t,
+		testResponse{status: 503},
+		testResponse{status: 429, retryAfter: "0"},
+		testResponse{status: 200, body: `{"id":1,"cn_name":"测试"}`},
+	)
+	card, err := newTestClient(s).GetCardByIDContext(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetCardByIDContext() error: %v", err)
+	}
+	if card.ID != 1 || card.CnName != "测试" {
+		t.Errorf("GetCardByIDContext() = %+v", card)
+	}
+	if got := s.requests.Load(); got != 3 {
+		t.Errorf("made %d attempts, want 3", got)
+	}
+}
+
+func TestGetCardWithoutRetries(t *testing.T) {
+	s := newTestServer(t, testResponse{status: 500})
+	_, err := newTestClient(s, WithRetry(0, 0)).GetCardByIDContext(context.Background(), 1)
+	if !errors.Is(err, ErrServer) {
+		t.Errorf("GetCardByIDContext() error = %v, want ErrServer", err)
+	}
+	if got := s.requests.Load(); got != 1 {
+		t.Errorf("made %d attempts, want 1", got)
+	}
+}
+
+func TestNetworkError(t *testing.T) {
+	s := newTestServer(t, testResponse{status: 200})
+	s.Close()
+	_, err := newTestClient(s).SearchCardsContext(context.Background(), "test", 0)
+	if !errors.Is(err, ErrNetwork) {
+		t.Errorf("SearchCardsContext() error = %v, want ErrNetwork", err)
+	}
+}
+
+func TestCancelledRequestIsNotRetried(t *testing.T) {
+	s := newTestServer(t, testResponse{status: 500})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := newTestClient(s).SearchCardsContext(ctx, "test", 0)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("SearchCardsContext() error = %v, want context.Canceled", err)
+	}
+	if got := s.requests.Load(); got > 1 {
+		t.Errorf("made %d attempts, want at most 1", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 4, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"0", 0},
+		{"120", 2 * time.Minute},
+		{"-5", 0},
+		{"Mon, 01 Apr 2024 12:00:30 GMT", 30 * time.Second},
+		{"Mon, 01 Apr 2024 11:59:00 GMT", 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	c := NewClient(WithRetry(3, 100*time.Millisecond))
+	for attempt, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		if got := c.backoff(attempt); got < want/2 || got > want {
+			t.Errorf("backoff(%d) = %s, want within [%s, %s]", attempt, got, want/2, want)
+		}
+	}
+	for _, attempt := range []int{20, 63, 100} {
+		if got := c.backoff(attempt); got < maxRetryDelay/2 || got > maxRetryDelay {
+			t.Errorf("backoff(%d) = %s, want within [%s, %s]", attempt, got, maxRetryDelay/2, maxRetryDelay)
+		}
+	}
+
+	if got := NewClient(WithRetry(3, 0)).backoff(2); got != 0 {
+		t.Errorf("backoff() without a base delay = %s, want 0", got)
+	}
+}
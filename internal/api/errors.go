@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when the requested card does not exist
+	ErrNotFound = errors.New("card not found")
+	// ErrRateLimited is returned when the API rejects requests for being too frequent
+	ErrRateLimited = errors.New("rate limited")
+	// ErrServer is returned when the API fails with a 5xx status code
+	ErrServer = errors.New("server error")
+	// ErrDecode is returned when the API response cannot be parsed
+	ErrDecode = errors.New("failed to decode response")
+	// ErrNetwork is returned when the API cannot be reached
+	ErrNetwork = errors.New("network error")
+)
+
+// StatusError is returned when the API responds with an unexpected status code.
+// It unwraps to ErrNotFound, ErrRateLimited or ErrServer where applicable.
+type StatusError struct {
+	StatusCode int
+	// RetryAfter is the delay requested by the Retry-After header, if any
+	RetryAfter time.Duration
+}
+
+// Error returns the error message
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API returned status code %d", e.StatusCode)
+}
+
+// Unwrap returns the error class of the status code
+func (e *StatusError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case e.StatusCode >= 500:
+		return ErrServer
+	default:
+		return nil
+	}
+}
+
+// Temporary reports whether the request may succeed if retried
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as
+// an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,125 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NameLang identifies one of the name fields of a card
+type NameLang int
+
+const (
+	// NameCN is cn_name, the ygocdb Chinese name
+	NameCN NameLang = iota
+	// NameSC is sc_name, the official Simplified Chinese name
+	NameSC
+	// NameMD is md_name, the Master Duel Chinese name
+	NameMD
+	// NameNWBBS is nwbbs_n, the NW forum translation
+	NameNWBBS
+	// NameCNOCG is cnocg_n, the CNOCG translation
+	NameCNOCG
+	// NameJP is jp_name, the Japanese name
+	NameJP
+	// NameEN is en_name, the English name
+	NameEN
+)
+
+// nameLangs lists the name languages in default fallback order
+var nameLangs = []struct {
+	lang  NameLang
+	code  string
+	label string
+}{
+	{NameCN, "cn", "中文"},
+	{NameSC, "sc", "简中"},
+	{NameMD, "md", "MD"},
+	{NameNWBBS, "nwbbs", "NW"},
+	{NameCNOCG, "cnocg", "CNOCG"},
+	{NameJP, "jp", "日文"},
+	{NameEN, "en", "英文"},
+}
+
+// ParseNameLang parses a name language code: cn, sc, md, nwbbs, cnocg, jp or en
+func ParseNameLang(code string) (NameLang, error) {
+	for _, l := range nameLangs {
+		if strings.EqualFold(l.code, code) {
+			return l.lang, nil
+		}
+	}
+	return NameCN, fmt.Errorf("invalid name language: %s (expected cn, sc, md, nwbbs, cnocg, jp or en)", code)
+}
+
+// String returns the code of the name language
+func (l NameLang) String() string {
+	if int(l) < len(nameLangs) {
+		return nameLangs[l].code
+	}
+	return fmt.Sprintf("NameLang(%d)", int(l))
+}
+
+// Label returns the display name of the name language
+func (l NameLang) Label() string {
+	if int(l) < len(nameLangs) {
+		return nameLangs[l].label
+	}
+	return l.String()
+}
+
+// Next returns the name language after l, cycling through all languages
+func (l NameLang) Next() NameLang {
+	return (l + 1) % NameLang(len(nameLangs))
+}
+
+// Name returns the card name in the given language, which may be empty
+func (c *Card) Name(lang NameLang) string {
+	switch lang {
+	case NameCN:
+		return c.CnName
+	case NameSC:
+		return c.ScName
+	case NameMD:
+		return c.MdName
+	case NameNWBBS:
+		return c.NwbbsN
+	case NameCNOCG:
+		return c.CnocgN
+	case NameJP:
+		return c.JpName
+	case NameEN:
+		return c.EnName
+	default:
+		return ""
+	}
+}
+
+// DisplayName returns the card name in the preferred language, falling back
+// to the other languages in default order and finally to the text name
+func (c *Card) DisplayName(preferred NameLang) string {
+	if name := c.Name(preferred); name != "" {
+		return name
+	}
+	for _, l := range nameLangs {
+		if name := c.Name(l.lang); name != "" {
+			return name
+		}
+	}
+	return c.Text.Name
+}
+
+// Alias is a name of a card in one language
+type Alias struct {
+	Lang NameLang
+	Name string
+}
+
+// Aliases returns every known name of the card in default order
+func (c *Card) Aliases() []Alias {
+	var aliases []Alias
+	for _, l := range nameLangs {
+		if name := c.Name(l.lang); name != "" {
+			aliases = append(aliases, Alias{Lang: l.lang, Name: name})
+		}
+	}
+	return aliases
+}
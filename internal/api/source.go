@@ -0,0 +1,30 @@
+package api
+
+import "context"
+
+// CardSource is a provider of card data. Client implements it on top of the
+// ygocdb API; other implementations can serve cards from offline databases,
+// caches or test fakes.
+type CardSource interface {
+	// SearchCardsContext searches for cards by query with pagination
+	SearchCardsContext(ctx context.Context, query string, start int) (*SearchResponse, error)
+	// GetCardByIDContext gets a card by its ID
+	GetCardByIDContext(ctx context.Context, cardID int) (*GetCardResponse, error)
+}
+
+// Ensure Client satisfies CardSource
+var _ CardSource = (*Client)(nil)
+
+// ArchetypeSource is implemented by card sources that can list every card of
+// an archetype directly
+type ArchetypeSource interface {
+	// CardsByArchetype returns the cards belonging to the archetype code
+	CardsByArchetype(ctx context.Context, archetype int) ([]Card, error)
+}
+
+// FilterSource is implemented by card sources that can scan all of their
+// cards, which allows searching by card data alone
+type FilterSource interface {
+	// FilterCards returns the cards for which match reports true
+	FilterCards(ctx context.Context, match func(*Card) bool) ([]Card, error)
+}
@@ -14,6 +14,18 @@ type Card struct {
 	EnName string `json:"en_name"`
 	Text   Text   `json:"text"`
 	Data   Data   `json:"data"`
+	// Alias is the passcode of the card this is an alternative artwork of,
+	// or 0 for original artworks
+	Alias int `json:"alias,omitempty"`
+}
+
+// BaseID returns the passcode of the original artwork of the card, which
+// identifies the card for copy limits and Forbidden/Limited lists
+func (c *Card) BaseID() int {
+	if c.Alias != 0 {
+		return c.Alias
+	}
+	return c.ID
 }
 
 // Text represents card text information
@@ -42,9 +54,8 @@ type SearchResponse struct {
 	Next   int    `json:"next"`
 }
 
-// GetCardResponse represents the response from get card API
+// GetCardResponse represents the response from get card API. It carries the
+// same fields as a search result.
 type GetCardResponse struct {
-	ID   int  `json:"id"`
-	Data Data `json:"data"`
-	Text Text `json:"text"`
+	Card
 }
\ No newline at end of file
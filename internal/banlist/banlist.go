@@ -0,0 +1,175 @@
+// Package banlist reads Forbidden/Limited lists from YGOPro lflist.conf files.
+package banlist
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Status is the limit status of a card on a list
+type Status int
+
+const (
+	// Forbidden cards cannot be used
+	Forbidden Status = iota
+	// Limited cards can be used once
+	Limited
+	// SemiLimited cards can be used twice
+	SemiLimited
+	// Unlimited cards can be used up to three times
+	Unlimited
+)
+
+// String returns the Chinese name of the status
+func (s Status) String() string {
+	switch s {
+	case Forbidden:
+		return "禁止"
+	case Limited:
+		return "限制"
+	case SemiLimited:
+		return "准限制"
+	}
+	return "无限制"
+}
+
+// Badge returns the status for display next to a card, or "" for
+// unlimited cards
+func (s Status) Badge() string {
+	if s == Unlimited {
+		return ""
+	}
+	return s.String()
+}
+
+// List is a named Forbidden/Limited list
+type List struct {
+	Name string
+	// Limits maps passcodes to the number of copies allowed
+	Limits map[int]int
+	// Whitelist forbids every card not in Limits, as in EDOPro "$whitelist" lists
+	Whitelist bool
+}
+
+// Limit returns the number of copies of a card allowed by the list
+func (l *List) Limit(id int) int {
+	if limit, ok := l.Limits[id]; ok {
+		return limit
+	}
+	if l.Whitelist {
+		return 0
+	}
+	return int(Unlimited)
+}
+
+// Status returns the limit status of a card on the list
+func (l *List) Status(id int) Status {
+	return Status(min(max(l.Limit(id), 0), int(Unlimited)))
+}
+
+// Parse parses an lflist.conf file. Each list starts with a "!name" line
+// followed by "passcode count --comment" lines; other lines starting with
+// "#" are comments.
+func Parse(r io.Reader) ([]*List, error) {
+	var (
+		lists   []*List
+		current *List
+	)
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "", strings.HasPrefix(text, "#"):
+			continue
+		case strings.HasPrefix(text, "!"):
+			current = &List{Name: strings.TrimSpace(text[1:]), Limits: map[int]int{}}
+			lists = append(lists, current)
+			continue
+		case current == nil:
+			return nil, fmt.Errorf("line %d: entry outside of a list", line)
+		case text == "$whitelist":
+			current.Whitelist = true
+			continue
+		}
+		
+		fields := strings.Fields(text)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: invalid entry %q", line, text)
+		}
+		id, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid passcode %q", line, fields[0])
+		}
+		limit, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid limit %q", line, fields[1])
+		}
+		current.Limits[id] = limit
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// Load reads the lists of one or more lflist.conf files in order. A list
+// replaces any earlier list with the same name.
+func Load(paths ...string) ([]*List, error) {
+	var lists []*List
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := Parse(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, list := range parsed {
+			if i := index(lists, list.Name); i >= 0 {
+				lists[i] = list
+			} else {
+				lists = append(lists, list)
+			}
+		}
+	}
+	return lists, nil
+}
+
+// Find returns the list with the given name, ignoring case. A name that is
+// not found exactly matches the only list whose name contains it, so
+// "tcg" chooses "2024.04 TCG".
+func Find(lists []*List, name string) (*List, error) {
+	if i := index(lists, name); i >= 0 {
+		return lists[i], nil
+	}
+	var found *List
+	for _, list := range lists {
+		if strings.Contains(strings.ToLower(list.Name), strings.ToLower(name)) {
+			if found != nil {
+				return nil, fmt.Errorf("ambiguous banlist %q: matches %q and %q", name, found.Name, list.Name)
+			}
+			found = list
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("unknown banlist %q", name)
+	}
+	return found, nil
+}
+
+// index returns the index of the list with the given name, ignoring case,
+// or -1
+func index(lists []*List, name string) int {
+	for i, list := range lists {
+		if strings.EqualFold(list.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
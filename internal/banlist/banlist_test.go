@@ -0,0 +1,155 @@
+package banlist
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testLFList = `#[2024.04 TCG][2024.01 OCG]
+!2024.04 TCG
+#forbidden
+14558127 0 --灰流丽
+89631139 1 --青眼白龙
+
+!2024.01 OCG
+  14558127 2 --spaces around the entry are ignored  
+!Genesys
+$whitelist
+89631139 3
+`
+
+func TestParse(t *testing.T) {
+	lists, err := Parse(strings.NewReader(testLFList))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if len(lists) != 3 {
+		t.Fatalf("Parse() returned %d lists, want 3", len(lists))
+	}
+	
+	tests := []struct {
+		list   int
+		id     int
+		limit  int
+		status Status
+	}{
+		{0, 14558127, 0, Forbidden},
+		{0, 89631139, 1, Limited},
+		{0, 1, 3, Unlimited},
+		{1, 14558127, 2, SemiLimited},
+		{1, 89631139, 3, Unlimited},
+		{2, 89631139, 3, Unlimited},
+		{2, 14558127, 0, Forbidden},
+	}
+	for _, tt := range tests {
+		list := lists[tt.list]
+		if got := list.Limit(tt.id); got != tt.limit {
+			t.Errorf("%s Limit(%d) = %d, want %d", list.Name, tt.id, got, tt.limit)
+		}
+		if got := list.Status(tt.id); got != tt.status {
+			t.Errorf("%s Status(%d) = %v, want %v", list.Name, tt.id, got, tt.status)
+		}
+	}
+	
+	for i, want := range []string{"2024.04 TCG", "2024.01 OCG", "Genesys"} {
+		if lists[i].Name != want {
+			t.Errorf("lists[%d].Name = %q, want %q", i, lists[i].Name, want)
+		}
+		if lists[i].Whitelist != (i == 2) {
+			t.Errorf("lists[%d].Whitelist = %v", i, lists[i].Whitelist)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"entry before a list", "#comment\n14558127 0\n", "line 2: entry outside of a list"},
+		{"whitelist before a list", "$whitelist\n", "line 1: entry outside of a list"},
+		{"missing limit", "!list\n14558127\n", `line 2: invalid entry "14558127"`},
+		{"invalid passcode", "!list\n\nabc 1\n", `line 3: invalid passcode "abc"`},
+		{"invalid limit", "!list\n14558127 x --comment\n", `line 2: invalid limit "x"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.file)); err == nil || err.Error() != tt.want {
+				t.Errorf("Parse() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "lflist.conf")
+	second := filepath.Join(dir, "expansions.conf")
+	if err := os.WriteFile(first, []byte("!A\n1 0\n!B\n1 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte("!b\n1 2\n!C\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	
+	lists, err := Load(first, second)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	var names []string
+	for _, list := range lists {
+		names = append(names, list.Name)
+	}
+	if got := strings.Join(names, ","); got != "A,b,C" {
+		t.Errorf("Load() lists = %s, want A,b,C", got)
+	}
+	if got := lists[1].Limit(1); got != 2 {
+		t.Errorf("replaced list Limit(1) = %d, want 2", got)
+	}
+	
+	if _, err := Load(filepath.Join(dir, "missing.conf")); err == nil {
+		t.Error("Load() of a missing file succeeded")
+	}
+}
+
+func TestFind(t *testing.T) {
+	lists, err := Parse(strings.NewReader("!2024.04 TCG\n!2024.01 OCG\n!2023.10 OCG\n!OCG\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	
+	tests := []struct {
+		name    string
+		want    string
+		wantErr string
+	}{
+		{"2024.04 TCG", "2024.04 TCG", ""},
+		{"2024.04 tcg", "2024.04 TCG", ""},
+		{"tcg", "2024.04 TCG", ""},
+		{"2023", "2023.10 OCG", ""},
+		// An exact match wins over the lists containing the name
+		{"ocg", "OCG", ""},
+		{"2024", "", `ambiguous banlist "2024": matches "2024.04 TCG" and "2024.01 OCG"`},
+		{"goat", "", `unknown banlist "goat"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := Find(lists, tt.name)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("Find(%q) error = %v, want %s", tt.name, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Find(%q) error: %v", tt.name, err)
+			}
+			if list.Name != tt.want {
+				t.Errorf("Find(%q) = %q, want %q", tt.name, list.Name, tt.want)
+			}
+		})
+	}
+}
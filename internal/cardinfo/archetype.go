@@ -0,0 +1,89 @@
+package cardinfo
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+//go:embed archetypes.conf
+var builtinArchetypes string
+
+var (
+	// archetypeNames maps archetype codes to their names
+	archetypeNames = mustParseSetnames(builtinArchetypes)
+	// archetypeMu guards archetypeNames
+	archetypeMu sync.RWMutex
+)
+
+// Setcodes splits a packed setcode into its up to four 16-bit archetype codes
+func Setcodes(setcode int) []int {
+	var codes []int
+	for i := 0; i < 4; i++ {
+		if code := (setcode >> (16 * i)) & 0xffff; code != 0 {
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// IsArchetype reports whether a card with the given packed setcode belongs
+// to the archetype. Like YGOPro, a sub-archetype such as 0x3008 also belongs
+// to its base archetype 0x8.
+func IsArchetype(setcode, archetype int) bool {
+	for _, code := range Setcodes(setcode) {
+		if code&0xfff == archetype&0xfff && code&archetype == archetype {
+			return true
+		}
+	}
+	return false
+}
+
+// LookupArchetype returns the name of an archetype code. Unknown
+// sub-archetypes fall back to the name of their base archetype.
+func LookupArchetype(code int) (string, bool) {
+	archetypeMu.RLock()
+	defer archetypeMu.RUnlock()
+
+	if name, ok := archetypeNames[code]; ok {
+		return name, true
+	}
+	if name, ok := archetypeNames[code&0xfff]; ok {
+		return name, true
+	}
+	return "", false
+}
+
+// FindArchetype returns the code of the archetype with the given name. When
+// several codes share a name the lowest one is returned.
+func FindArchetype(name string) (int, bool) {
+	archetypeMu.RLock()
+	defer archetypeMu.RUnlock()
+
+	found, ok := 0, false
+	for code, n := range archetypeNames {
+		if n == name && (!ok || code < found) {
+			found, ok = code, true
+		}
+	}
+	return found, ok
+}
+
+// ArchetypeName returns the name of an archetype code, or a placeholder
+// showing the code if it is unknown
+func ArchetypeName(code int) string {
+	if name, ok := LookupArchetype(code); ok {
+		return name
+	}
+	return fmt.Sprintf("未知系列(%#x)", code)
+}
+
+// mustParseSetnames parses the built-in archetype names
+func mustParseSetnames(text string) map[int]string {
+	s, err := ParseStrings(strings.NewReader(text))
+	if err != nil {
+		panic(fmt.Sprintf("invalid built-in archetype names: %v", err))
+	}
+	return s.Setnames
+}
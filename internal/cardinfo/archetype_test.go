@@ -0,0 +1,82 @@
+package cardinfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetcodes(t *testing.T) {
+	tests := []struct {
+		setcode int
+		want    []int
+	}{
+		{0, nil},
+		{0x8, []int{0x8}},
+		{0x3008, []int{0x3008}},
+		// Performapal Odd-Eyes Light Phoenix: Performapal and Odd-Eyes
+		{0x99009f, []int{0x9f, 0x99}},
+		{0x10af00af, []int{0xaf, 0x10af}},
+		{0x1234_0000_0000_0008, []int{0x8, 0x1234}},
+		{0x4_0003_0002_0001, []int{0x1, 0x2, 0x3, 0x4}},
+	}
+	for _, tt := range tests {
+		if got := Setcodes(tt.setcode); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Setcodes(%#x) = %#x, want %#x", tt.setcode, got, tt.want)
+		}
+	}
+}
+
+func TestIsArchetype(t *testing.T) {
+	tests := []struct {
+		name      string
+		setcode   int
+		archetype int
+		want      bool
+	}{
+		{"base archetype", 0x8, 0x8, true},
+		{"sub-archetype belongs to its base", 0x3008, 0x8, true},
+		{"sub-archetype", 0x3008, 0x3008, true},
+		{"sub-archetype bits are a superset", 0x3008, 0x1008, true},
+		{"base does not belong to a sub-archetype", 0x8, 0x3008, false},
+		{"sibling sub-archetype", 0x3008, 0x5008, false},
+		{"sibling sub-archetype with shared bits", 0x6008, 0xa008, false},
+		{"different base", 0x3008, 0x108, false},
+		{"DDD is DD", 0x10af, 0xaf, true},
+		{"DD is not DDD", 0xaf, 0x10af, false},
+		{"second setcode", 0x99009f, 0x99, true},
+		{"second setcode sub-archetype", 0x10af009f, 0xaf, true},
+		{"no setcode", 0, 0x8, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsArchetype(tt.setcode, tt.archetype); got != tt.want {
+				t.Errorf("IsArchetype(%#x, %#x) = %v, want %v", tt.setcode, tt.archetype, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchetypeNames(t *testing.T) {
+	tests := []struct {
+		code int
+		want string
+	}{
+		{0x8, "英雄"},
+		{0x3008, "元素英雄"},
+		// Unknown sub-archetypes are named after their base archetype
+		{0xf008, "英雄"},
+		{0xfff, "未知系列(0xfff)"},
+	}
+	for _, tt := range tests {
+		if got := ArchetypeName(tt.code); got != tt.want {
+			t.Errorf("ArchetypeName(%#x) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+
+	if code, ok := FindArchetype("元素英雄"); !ok || code != 0x3008 {
+		t.Errorf("FindArchetype(元素英雄) = %#x, %v", code, ok)
+	}
+	if _, ok := FindArchetype("不存在的系列"); ok {
+		t.Error("FindArchetype of an unknown name succeeded")
+	}
+}
@@ -0,0 +1,123 @@
+package cardinfo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OT is the YGOPro card scope (region) bitmask
+type OT int
+
+// Card scopes
+const (
+	OTOCG        OT = 0x1
+	OTTCG        OT = 0x2
+	OTAnime      OT = 0x4
+	OTIllegal    OT = 0x8
+	OTVideoGame  OT = 0x10
+	OTCustom     OT = 0x20
+	OTSpeed      OT = 0x40
+	OTPrerelease OT = 0x100
+	OTRush       OT = 0x200
+	OTLegend     OT = 0x400
+	OTHidden     OT = 0x1000
+
+	// otUnofficial are the scopes of cards that are not legal in OCG or TCG play
+	otUnofficial = OTAnime | OTIllegal | OTVideoGame | OTCustom
+)
+
+// IsOCG reports whether the card is released in the OCG
+func (o OT) IsOCG() bool {
+	return o&OTOCG != 0
+}
+
+// IsTCG reports whether the card is released in the TCG
+func (o OT) IsTCG() bool {
+	return o&OTTCG != 0
+}
+
+// IsUnreleased reports whether the card is announced but not yet released
+func (o OT) IsUnreleased() bool {
+	return o&OTPrerelease != 0
+}
+
+// IsUnofficial reports whether the card only exists in the anime, video
+// games or as a custom card
+func (o OT) IsUnofficial() bool {
+	return !o.IsOCG() && !o.IsTCG() && o&otUnofficial != 0
+}
+
+// Badge returns a short label of the card scope, e.g. "OCG/TCG", "OCG",
+// "TCG" or "动画/自制", with "未发售" appended for unreleased cards
+func (o OT) Badge() string {
+	var badge string
+	switch {
+	case o.IsOCG() && o.IsTCG():
+		badge = "OCG/TCG"
+	case o.IsOCG():
+		badge = "OCG"
+	case o.IsTCG():
+		badge = "TCG"
+	case o.IsUnofficial():
+		badge = "动画/自制"
+	}
+	if o.IsUnreleased() {
+		badge = strings.TrimSpace(badge + " 未发售")
+	}
+	return badge
+}
+
+// Region is the card pool a player plays with
+type Region int
+
+const (
+	// RegionAll shows cards of every region
+	RegionAll Region = iota
+	// RegionOCG shows only cards released in the OCG
+	RegionOCG
+	// RegionTCG shows only cards released in the TCG
+	RegionTCG
+)
+
+// ParseRegion parses a region name: all, ocg or tcg
+func ParseRegion(name string) (Region, error) {
+	switch strings.ToLower(name) {
+	case "all", "":
+		return RegionAll, nil
+	case "ocg":
+		return RegionOCG, nil
+	case "tcg":
+		return RegionTCG, nil
+	default:
+		return RegionAll, fmt.Errorf("invalid region: %s (expected all, ocg or tcg)", name)
+	}
+}
+
+// String returns the name of the region
+func (r Region) String() string {
+	switch r {
+	case RegionOCG:
+		return "OCG"
+	case RegionTCG:
+		return "TCG"
+	default:
+		return "全部"
+	}
+}
+
+// Next returns the region after r, cycling through all regions
+func (r Region) Next() Region {
+	return (r + 1) % (RegionTCG + 1)
+}
+
+// Allows reports whether a card of the given scope is available in the region
+func (r Region) Allows(ot OT) bool {
+	switch r {
+	case RegionOCG:
+		return ot.IsOCG()
+	case RegionTCG:
+		return ot.IsTCG()
+	default:
+		return true
+	}
+}
@@ -0,0 +1,144 @@
+package cardinfo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Race is the YGOPro monster race (type) bitmask
+type Race int
+
+// Monster races
+const (
+	RaceWarrior      Race = 0x1
+	RaceSpellcaster  Race = 0x2
+	RaceFairy        Race = 0x4
+	RaceFiend        Race = 0x8
+	RaceZombie       Race = 0x10
+	RaceMachine      Race = 0x20
+	RaceAqua         Race = 0x40
+	RacePyro         Race = 0x80
+	RaceRock         Race = 0x100
+	RaceWingedBeast  Race = 0x200
+	RacePlant        Race = 0x400
+	RaceInsect       Race = 0x800
+	RaceThunder      Race = 0x1000
+	RaceDragon       Race = 0x2000
+	RaceBeast        Race = 0x4000
+	RaceBeastWarrior Race = 0x8000
+	RaceDinosaur     Race = 0x10000
+	RaceFish         Race = 0x20000
+	RaceSeaSerpent   Race = 0x40000
+	RaceReptile      Race = 0x80000
+	RacePsychic      Race = 0x100000
+	RaceDivine       Race = 0x200000
+	RaceCreatorGod   Race = 0x400000
+	RaceWyrm         Race = 0x800000
+	RaceCyberse      Race = 0x1000000
+	RaceIllusion     Race = 0x2000000
+)
+
+// raceNames lists the races in bit order
+var raceNames = []struct {
+	race Race
+	cn   string
+	en   string
+}{
+	{RaceWarrior, "战士", "warrior"},
+	{RaceSpellcaster, "魔法师", "spellcaster"},
+	{RaceFairy, "天使", "fairy"},
+	{RaceFiend, "恶魔", "fiend"},
+	{RaceZombie, "不死", "zombie"},
+	{RaceMachine, "机械", "machine"},
+	{RaceAqua, "水", "aqua"},
+	{RacePyro, "炎", "pyro"},
+	{RaceRock, "岩石", "rock"},
+	{RaceWingedBeast, "鸟兽", "wingedbeast"},
+	{RacePlant, "植物", "plant"},
+	{RaceInsect, "昆虫", "insect"},
+	{RaceThunder, "雷", "thunder"},
+	{RaceDragon, "龙", "dragon"},
+	{RaceBeast, "兽", "beast"},
+	{RaceBeastWarrior, "兽战士", "beastwarrior"},
+	{RaceDinosaur, "恐龙", "dinosaur"},
+	{RaceFish, "鱼", "fish"},
+	{RaceSeaSerpent, "海龙", "seaserpent"},
+	{RaceReptile, "爬虫类", "reptile"},
+	{RacePsychic, "念动力", "psychic"},
+	{RaceDivine, "幻神兽", "divine"},
+	{RaceCreatorGod, "创造神", "creatorgod"},
+	{RaceWyrm, "幻龙", "wyrm"},
+	{RaceCyberse, "电子界", "cyberse"},
+	{RaceIllusion, "幻想魔", "illusion"},
+}
+
+// String returns the Chinese name of the race
+func (r Race) String() string {
+	for _, name := range raceNames {
+		if name.race == r {
+			return name.cn
+		}
+	}
+	return fmt.Sprintf("未知种族(%d)", int(r))
+}
+
+// ParseRace returns the race with the given Chinese or English name
+func ParseRace(name string) (Race, bool) {
+	name = strings.ToLower(name)
+	for _, n := range raceNames {
+		if n.cn == name || n.en == name {
+			return n.race, true
+		}
+	}
+	return 0, false
+}
+
+// Attribute is the YGOPro monster attribute bitmask
+type Attribute int
+
+// Monster attributes
+const (
+	AttributeEarth  Attribute = 0x1
+	AttributeWater  Attribute = 0x2
+	AttributeFire   Attribute = 0x4
+	AttributeWind   Attribute = 0x8
+	AttributeLight  Attribute = 0x10
+	AttributeDark   Attribute = 0x20
+	AttributeDivine Attribute = 0x40
+)
+
+// attributeNames lists the attributes in bit order
+var attributeNames = []struct {
+	attribute Attribute
+	cn        string
+	en        string
+}{
+	{AttributeEarth, "地", "earth"},
+	{AttributeWater, "水", "water"},
+	{AttributeFire, "炎", "fire"},
+	{AttributeWind, "风", "wind"},
+	{AttributeLight, "光", "light"},
+	{AttributeDark, "暗", "dark"},
+	{AttributeDivine, "神", "divine"},
+}
+
+// String returns the Chinese name of the attribute
+func (a Attribute) String() string {
+	for _, name := range attributeNames {
+		if name.attribute == a {
+			return name.cn
+		}
+	}
+	return fmt.Sprintf("未知属性(%d)", int(a))
+}
+
+// ParseAttribute returns the attribute with the given Chinese or English name
+func ParseAttribute(name string) (Attribute, bool) {
+	name = strings.ToLower(name)
+	for _, n := range attributeNames {
+		if n.cn == name || n.en == name {
+			return n.attribute, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,190 @@
+package cardinfo
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// RubySegment is a part of a Japanese name with its reading. Ruby is empty
+// when the base needs no annotation, e.g. kana.
+type RubySegment struct {
+	Base string
+	Ruby string
+}
+
+// ParseRuby splits a Japanese name into segments annotated with their
+// readings. The ruby may be given in "[灰流|はる]うらら" notation or as the
+// reading of the whole name, which is then aligned with the kana of the
+// name. Readings that cannot be aligned annotate the whole name.
+func ParseRuby(name, ruby string) []RubySegment {
+	switch {
+	case ruby == "":
+		return []RubySegment{{Base: name}}
+	case strings.Contains(ruby, "|"):
+		return parseRubyNotation(ruby)
+	case FoldKana(ruby) == FoldKana(name):
+		return []RubySegment{{Base: name}}
+	}
+	if segments, ok := alignRuby(name, ruby); ok {
+		return segments
+	}
+	return []RubySegment{{Base: name, Ruby: ruby}}
+}
+
+// Reading returns the reading of a name from its ruby in either notation
+func Reading(ruby string) string {
+	if !strings.Contains(ruby, "|") {
+		return ruby
+	}
+	var b strings.Builder
+	for _, segment := range parseRubyNotation(ruby) {
+		if segment.Ruby != "" {
+			b.WriteString(segment.Ruby)
+		} else {
+			b.WriteString(segment.Base)
+		}
+	}
+	return b.String()
+}
+
+// HasRuby reports whether any segment is annotated
+func HasRuby(segments []RubySegment) bool {
+	for _, segment := range segments {
+		if segment.Ruby != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderRuby renders the segments as two lines, with each reading above
+// its base
+func RenderRuby(segments []RubySegment) (top, bottom string) {
+	var t, b strings.Builder
+	for _, segment := range segments {
+		width := max(runewidth.StringWidth(segment.Base), runewidth.StringWidth(segment.Ruby))
+		t.WriteString(runewidth.FillRight(segment.Ruby, width))
+		b.WriteString(runewidth.FillRight(segment.Base, width))
+	}
+	return strings.TrimRight(t.String(), " "), strings.TrimRight(b.String(), " ")
+}
+
+// parseRubyNotation parses "[base|ruby]" notation
+func parseRubyNotation(ruby string) []RubySegment {
+	var segments []RubySegment
+	for ruby != "" {
+		open := strings.IndexByte(ruby, '[')
+		if open < 0 {
+			segments = append(segments, RubySegment{Base: ruby})
+			break
+		}
+		end := strings.IndexByte(ruby[open:], ']')
+		if end < 0 {
+			segments = append(segments, RubySegment{Base: ruby})
+			break
+		}
+		if open > 0 {
+			segments = append(segments, RubySegment{Base: ruby[:open]})
+		}
+		base, reading, _ := strings.Cut(ruby[open+1:open+end], "|")
+		segments = append(segments, RubySegment{Base: base, Ruby: reading})
+		ruby = ruby[open+end+1:]
+	}
+	return segments
+}
+
+// alignRuby aligns the reading of a whole name with the kana runs of the
+// name, so that only the other runs are annotated
+func alignRuby(name, ruby string) ([]RubySegment, bool) {
+	runs := splitKanaRuns(name)
+	reading := []rune(ruby)
+	folded := []rune(FoldKana(ruby))
+	pos := 0
+	var segments []RubySegment
+	for i, run := range runs {
+		if isKanaRun(run) {
+			kana := []rune(FoldKana(run))
+			if !hasPrefixAt(folded, kana, pos) {
+				return nil, false
+			}
+			segments = append(segments, RubySegment{Base: run})
+			pos += len(kana)
+			continue
+		}
+
+		// The reading of this run extends to the next kana run
+		end := len(reading)
+		if i+1 < len(runs) {
+			kana := []rune(FoldKana(runs[i+1]))
+			end = -1
+			for j := pos + 1; j+len(kana) <= len(folded); j++ {
+				if hasPrefixAt(folded, kana, j) {
+					end = j
+					break
+				}
+			}
+		}
+		if end <= pos {
+			return nil, false
+		}
+		segments = append(segments, RubySegment{Base: run, Ruby: string(reading[pos:end])})
+		pos = end
+	}
+	return segments, pos == len(reading)
+}
+
+// splitKanaRuns splits text into alternating runs of kana and other characters
+func splitKanaRuns(text string) []string {
+	var runs []string
+	var run []rune
+	for _, r := range text {
+		if len(run) > 0 && isKana(r) != isKana(run[0]) {
+			runs = append(runs, string(run))
+			run = run[:0]
+		}
+		run = append(run, r)
+	}
+	if len(run) > 0 {
+		runs = append(runs, string(run))
+	}
+	return runs
+}
+
+// isKanaRun reports whether the run consists of kana
+func isKanaRun(run string) bool {
+	for _, r := range run {
+		return isKana(r)
+	}
+	return false
+}
+
+// isKana reports whether r is hiragana, katakana or the long vowel mark
+func isKana(r rune) bool {
+	return unicode.In(r, unicode.Hiragana, unicode.Katakana) || r == 'ー'
+}
+
+// hasPrefixAt reports whether s contains prefix at index i
+func hasPrefixAt(s, prefix []rune, i int) bool {
+	if i+len(prefix) > len(s) {
+		return false
+	}
+	for j, r := range prefix {
+		if s[i+j] != r {
+			return false
+		}
+	}
+	return true
+}
+
+// FoldKana folds katakana to hiragana so readings compare regardless of
+// the kana used
+func FoldKana(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'ァ' && r <= 'ヶ' {
+			return r - 0x60
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,93 @@
+package cardinfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseRuby(t *testing.T) {
+	tests := []struct {
+		name string
+		base string
+		ruby string
+		want []RubySegment
+	}{
+		{"no ruby", "灰流うらら", "", []RubySegment{{Base: "灰流うらら"}}},
+		{"kana only", "ブラック・マジシャン", "ぶらっく・まじしゃん", []RubySegment{{Base: "ブラック・マジシャン"}}},
+		{"kanji then kana", "灰流うらら", "はるうらら", []RubySegment{{Base: "灰流", Ruby: "はる"}, {Base: "うらら"}}},
+		{"kanji, kana and letters", "増殖するG", "ぞうしょくするじー", []RubySegment{
+			{Base: "増殖", Ruby: "ぞうしょく"}, {Base: "する"}, {Base: "G", Ruby: "じー"},
+		}},
+		{"kana then kanji", "ドドドウィッチ", "どどどうぃっち", []RubySegment{{Base: "ドドドウィッチ"}}},
+		{"katakana reading of hiragana", "墓穴の指名者", "ハカアナのしめいしゃ", []RubySegment{
+			{Base: "墓穴", Ruby: "ハカアナ"}, {Base: "の"}, {Base: "指名者", Ruby: "しめいしゃ"},
+		}},
+		{"kana repeated in the reading", "神の宣告", "かみのせんこく", []RubySegment{
+			{Base: "神", Ruby: "かみ"}, {Base: "の"}, {Base: "宣告", Ruby: "せんこく"},
+		}},
+		{"unaligned reading", "青眼の白龍", "ブルーアイズ・ホワイト・ドラゴン", []RubySegment{
+			{Base: "青眼の白龍", Ruby: "ブルーアイズ・ホワイト・ドラゴン"},
+		}},
+		{"mismatched kana", "灰流うらら", "はるうらか", []RubySegment{{Base: "灰流うらら", Ruby: "はるうらか"}}},
+		{"notation", "灰流うらら", "[灰流|はる]うらら", []RubySegment{{Base: "灰流", Ruby: "はる"}, {Base: "うらら"}}},
+		{"notation with several readings", "増殖するG", "[増殖|ぞうしょく]する[G|じー]", []RubySegment{
+			{Base: "増殖", Ruby: "ぞうしょく"}, {Base: "する"}, {Base: "G", Ruby: "じー"},
+		}},
+		{"unclosed bracket", "灰流うらら", "[灰流|はるうらら", []RubySegment{{Base: "[灰流|はるうらら"}}},
+		{"closing bracket first", "灰流うらら", "うら]ら[灰|はい]", []RubySegment{{Base: "うら]ら"}, {Base: "灰", Ruby: "はい"}}},
+		{"bracket without reading", "灰流うらら", "[灰流]うらら|", []RubySegment{{Base: "灰流"}, {Base: "うらら|"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseRuby(tt.base, tt.ruby); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRuby(%q, %q) = %+v, want %+v", tt.base, tt.ruby, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReading(t *testing.T) {
+	tests := map[string]string{
+		"":           "",
+		"はるうらら":      "はるうらら",
+		"[灰流|はる]うらら": "はるうらら",
+		"[増殖|ぞうしょく]する[G|じー]": "ぞうしょくするじー",
+		"[灰流|はるうらら":          "[灰流|はるうらら",
+	}
+	for ruby, want := range tests {
+		if got := Reading(ruby); got != want {
+			t.Errorf("Reading(%q) = %q, want %q", ruby, got, want)
+		}
+	}
+}
+
+func TestRenderRuby(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []RubySegment
+		top      string
+		bottom   string
+	}{
+		{"reading as wide as the base", []RubySegment{{Base: "灰流", Ruby: "はる"}, {Base: "うらら"}}, "はる", "灰流うらら"},
+		{"reading wider than the base", []RubySegment{{Base: "増殖", Ruby: "ぞうしょく"}, {Base: "する"}, {Base: "G", Ruby: "じー"}},
+			"ぞうしょく    じー", "増殖      するG"},
+		{"no reading", []RubySegment{{Base: "ドドドウィッチ"}}, "", "ドドドウィッチ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			top, bottom := RenderRuby(tt.segments)
+			if top != tt.top || bottom != tt.bottom {
+				t.Errorf("RenderRuby() =\n%q\n%q\nwant\n%q\n%q", top, bottom, tt.top, tt.bottom)
+			}
+			if got, want := HasRuby(tt.segments), tt.top != ""; got != want {
+				t.Errorf("HasRuby() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFoldKana(t *testing.T) {
+	if got := FoldKana("ブラック・マジシャンGirl"); got != "ぶらっく・まじしゃんGirl" {
+		t.Errorf("FoldKana() = %q", got)
+	}
+}
@@ -0,0 +1,118 @@
+package cardinfo
+
+import (
+	"strconv"
+	"strings"
+)
+
+const (
+	// UnknownStat is the ATK/DEF value of a "?" stat
+	UnknownStat = -2
+)
+
+// LinkMarker is the YGOPro link marker bitmask stored in the DEF field of
+// Link monsters
+type LinkMarker int
+
+// Link markers
+const (
+	LinkBottomLeft  LinkMarker = 0x1
+	LinkBottom      LinkMarker = 0x2
+	LinkBottomRight LinkMarker = 0x4
+	LinkLeft        LinkMarker = 0x8
+	LinkRight       LinkMarker = 0x20
+	LinkTopLeft     LinkMarker = 0x40
+	LinkTop         LinkMarker = 0x80
+	LinkTopRight    LinkMarker = 0x100
+)
+
+// linkGrid lays out the link markers as they appear on the card, with the
+// card itself in the centre
+var linkGrid = [3][3]struct {
+	marker LinkMarker
+	arrow  string
+}{
+	{{LinkTopLeft, "↖"}, {LinkTop, "↑"}, {LinkTopRight, "↗"}},
+	{{LinkLeft, "←"}, {0, "□"}, {LinkRight, "→"}},
+	{{LinkBottomLeft, "↙"}, {LinkBottom, "↓"}, {LinkBottomRight, "↘"}},
+}
+
+// Has reports whether the marker is set
+func (m LinkMarker) Has(marker LinkMarker) bool {
+	return m&marker != 0
+}
+
+// Diagram renders the markers as a 3x3 grid, with unset markers shown as
+// dots
+func (m LinkMarker) Diagram() string {
+	var b strings.Builder
+	for i, row := range linkGrid {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		for j, cell := range row {
+			if j > 0 {
+				b.WriteString(" ")
+			}
+			if cell.marker == 0 || m.Has(cell.marker) {
+				b.WriteString(cell.arrow)
+			} else {
+				b.WriteString("·")
+			}
+		}
+	}
+	return b.String()
+}
+
+// Stats holds the decoded level and combat stats of a monster
+type Stats struct {
+	// Level is the Level, Rank or Link rating depending on the monster type
+	Level       int
+	LeftScale   int
+	RightScale  int
+	Atk         int
+	Def         int
+	LinkMarkers LinkMarker
+}
+
+// DecodeStats decodes the packed level and DEF fields of card data. The low
+// byte of level holds the Level, Rank or Link rating; for Pendulum monsters
+// the upper bytes hold the left and right scales. For Link monsters def holds
+// the link markers and there is no DEF.
+func DecodeStats(typ Type, level, atk, def int) Stats {
+	stats := Stats{
+		Level: level & 0xff,
+		Atk:   atk,
+		Def:   def,
+	}
+	if typ.Has(TypePendulum) {
+		stats.LeftScale = (level >> 24) & 0xff
+		stats.RightScale = (level >> 16) & 0xff
+	}
+	if typ.Has(TypeLink) {
+		stats.LinkMarkers = LinkMarker(def)
+		stats.Def = 0
+	}
+	return stats
+}
+
+// LevelLabel returns the Chinese name of the level field of a monster:
+// 星级 (Level), 阶级 (Rank) for Xyz or 连接值 (Link rating) for Link monsters
+func LevelLabel(typ Type) string {
+	switch {
+	case typ.Has(TypeXyz):
+		return "阶级"
+	case typ.Has(TypeLink):
+		return "连接值"
+	default:
+		return "星级"
+	}
+}
+
+// FormatStat formats an ATK or DEF value, showing "?" for unknown values
+func FormatStat(value int) string {
+	if value == UnknownStat {
+		return "?"
+	}
+	return strconv.Itoa(value)
+}
@@ -0,0 +1,96 @@
+package cardinfo
+
+import "testing"
+
+func TestDecodeStats(t *testing.T) {
+	tests := []struct {
+		name            string
+		typ             Type
+		level, atk, def int
+		want            Stats
+	}{
+		{
+			"Odd-Eyes Pendulum Dragon", 0x1000021, 0x04040007, 2500, 2000,
+			Stats{Level: 7, LeftScale: 4, RightScale: 4, Atk: 2500, Def: 2000},
+		},
+		{
+			// Printed cards have equal scales, so distinct scales check
+			// which byte holds which scale
+			"different scales", 0x1000021, 0x08010004, 1200, 0,
+			Stats{Level: 4, LeftScale: 8, RightScale: 1, Atk: 1200},
+		},
+		{
+			"Decode Talker", 0x4000021, 3, 2300, 0x85,
+			Stats{Level: 3, Atk: 2300, LinkMarkers: LinkTop | LinkBottomLeft | LinkBottomRight},
+		},
+		{
+			"The Winged Dragon of Ra", 0x21, 10, UnknownStat, UnknownStat,
+			Stats{Level: 10, Atk: UnknownStat, Def: UnknownStat},
+		},
+		{
+			// The upper bytes of a non-pendulum level are not scales
+			"Number 39: Utopia", 0x800021, 4, 2500, 2000,
+			Stats{Level: 4, Atk: 2500, Def: 2000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DecodeStats(tt.typ, tt.level, tt.atk, tt.def); got != tt.want {
+				t.Errorf("DecodeStats() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLinkMarkerDiagram(t *testing.T) {
+	tests := []struct {
+		name    string
+		markers LinkMarker
+		want    string
+	}{
+		{"Decode Talker", 0x85, "· ↑ ·\n· □ ·\n↙ · ↘"},
+		{"Firewall Dragon", 0xaa, "· ↑ ·\n← □ →\n· ↓ ·"},
+		{"no markers", 0, "· · ·\n· □ ·\n· · ·"},
+		{"all markers", 0x1ef, "↖ ↑ ↗\n← □ →\n↙ ↓ ↘"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.markers.Diagram(); got != tt.want {
+				t.Errorf("Diagram() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatStat(t *testing.T) {
+	tests := []struct {
+		value int
+		want  string
+	}{
+		{UnknownStat, "?"},
+		{0, "0"},
+		{2500, "2500"},
+	}
+	for _, tt := range tests {
+		if got := FormatStat(tt.value); got != tt.want {
+			t.Errorf("FormatStat(%d) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestLevelLabel(t *testing.T) {
+	tests := []struct {
+		typ  Type
+		want string
+	}{
+		{0x21, "星级"},
+		{0x1000021, "星级"},
+		{0x800021, "阶级"},
+		{0x4000021, "连接值"},
+	}
+	for _, tt := range tests {
+		if got := LevelLabel(tt.typ); got != tt.want {
+			t.Errorf("LevelLabel(%#x) = %q, want %q", int(tt.typ), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package cardinfo
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Strings holds the named strings of YGOPro strings.conf files
+type Strings struct {
+	// System holds the "!system" client strings
+	System map[int]string
+	// Victory holds the "!victory" reason strings
+	Victory map[int]string
+	// Counters holds the "!counter" counter names
+	Counters map[int]string
+	// Setnames holds the "!setname" archetype names
+	Setnames map[int]string
+}
+
+// Counter represents a named counter
+type Counter struct {
+	Code int
+	Name string
+}
+
+var (
+	// counterNames maps counter codes to their names
+	counterNames = map[int]string{}
+	// counterMu guards counterNames
+	counterMu sync.RWMutex
+)
+
+// NewStrings creates an empty set of strings
+func NewStrings() *Strings {
+	return &Strings{
+		System:   map[int]string{},
+		Victory:  map[int]string{},
+		Counters: map[int]string{},
+		Setnames: map[int]string{},
+	}
+}
+
+// ParseStrings parses a strings.conf file. Lines look like
+// "!setname 0x8 英雄" where the name may be followed by the original name
+// after a tab; comments start with "#".
+func ParseStrings(r io.Reader) (*Strings, error) {
+	s := NewStrings()
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(text, "!") {
+			continue
+		}
+
+		split := strings.IndexFunc(text, unicode.IsSpace)
+		if split < 0 {
+			continue
+		}
+		var table map[int]string
+		switch text[1:split] {
+		case "system":
+			table = s.System
+		case "victory":
+			table = s.Victory
+		case "counter":
+			table = s.Counters
+		case "setname":
+			table = s.Setnames
+		default:
+			continue
+		}
+
+		rest := strings.TrimSpace(text[split:])
+		split = strings.IndexFunc(rest, unicode.IsSpace)
+		if split < 0 {
+			return nil, fmt.Errorf("line %d: missing name", line)
+		}
+		code, err := strconv.ParseInt(rest[:split], 0, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid code %q", line, rest[:split])
+		}
+		name, _, _ := strings.Cut(strings.TrimSpace(rest[split:]), "\t")
+		table[int(code)] = strings.TrimSpace(name)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// LoadStrings reads and merges strings.conf files. Strings from later files
+// replace strings with the same code from earlier ones.
+func LoadStrings(paths ...string) (*Strings, error) {
+	merged := NewStrings()
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		s, err := ParseStrings(file)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		merged.Merge(s)
+	}
+	return merged, nil
+}
+
+// Merge copies the strings of other into s, replacing existing ones
+func (s *Strings) Merge(other *Strings) {
+	for _, pair := range []struct{ dst, src map[int]string }{
+		{s.System, other.System},
+		{s.Victory, other.Victory},
+		{s.Counters, other.Counters},
+		{s.Setnames, other.Setnames},
+	} {
+		for code, name := range pair.src {
+			pair.dst[code] = name
+		}
+	}
+}
+
+// Use makes the archetype and counter names of s the ones used to describe
+// cards, replacing built-in names with the same code
+func (s *Strings) Use() {
+	archetypeMu.Lock()
+	for code, name := range s.Setnames {
+		archetypeNames[code] = name
+	}
+	archetypeMu.Unlock()
+
+	counterMu.Lock()
+	for code, name := range s.Counters {
+		counterNames[code] = name
+	}
+	counterMu.Unlock()
+}
+
+// CounterName returns the name of a counter code
+func CounterName(code int) (string, bool) {
+	counterMu.RLock()
+	defer counterMu.RUnlock()
+
+	name, ok := counterNames[code]
+	return name, ok
+}
+
+// CountersIn returns the known counters whose names appear in text, ordered
+// by code
+func CountersIn(text string) []Counter {
+	counterMu.RLock()
+	defer counterMu.RUnlock()
+
+	var counters []Counter
+	for code, name := range counterNames {
+		// Skip single characters, they match unrelated text
+		if len([]rune(name)) > 1 && strings.Contains(text, name) {
+			counters = append(counters, Counter{Code: code, Name: name})
+		}
+	}
+	sort.Slice(counters, func(i, j int) bool {
+		return counters[i].Code < counters[j].Code
+	})
+	return counters
+}
@@ -0,0 +1,99 @@
+package cardinfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testStrings = `#The first line is a comment
+!system 1 通常召唤
+!victory 0x10 因「艾克佐迪亚」的效果获胜
+  !counter 0x1 魔力指示物
+!counter 0x1041	魔力指示物
+#!setname 0x1 commented out
+!setname 0x8 英雄	HERO
+!setname 0x3008 元素英雄
+!setname 12 十二
+!unknown 0x1 ignored
+!setname
+plain text is ignored
+`
+
+func TestParseStrings(t *testing.T) {
+	s, err := ParseStrings(strings.NewReader(testStrings))
+	if err != nil {
+		t.Fatalf("ParseStrings() error: %v", err)
+	}
+	want := &Strings{
+		System:   map[int]string{1: "通常召唤"},
+		Victory:  map[int]string{0x10: "因「艾克佐迪亚」的效果获胜"},
+		Counters: map[int]string{0x1: "魔力指示物", 0x1041: "魔力指示物"},
+		Setnames: map[int]string{0x8: "英雄", 0x3008: "元素英雄", 12: "十二"},
+	}
+	if !reflect.DeepEqual(s, want) {
+		t.Errorf("ParseStrings() = %+v, want %+v", s, want)
+	}
+}
+
+func TestParseStringsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"missing name", "#comment\n!setname 0x8\n", "line 2: missing name"},
+		{"invalid hex code", "!counter 0xzz 指示物\n", `line 1: invalid code "0xzz"`},
+		{"invalid code", "\n\n!setname 英雄 0x8\n", `line 3: invalid code "英雄"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseStrings(strings.NewReader(tt.text)); err == nil || err.Error() != tt.want {
+				t.Errorf("ParseStrings() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadStrings(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "strings.conf")
+	second := filepath.Join(dir, "expansions.conf")
+	if err := os.WriteFile(first, []byte("!setname 0xff1 旧名称\n!counter 0xff1 测试指示物\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(second, []byte("!setname 0xff1 新名称\n!setname 0xff2 新系列\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := LoadStrings(first, second)
+	if err != nil {
+		t.Fatalf("LoadStrings() error: %v", err)
+	}
+	if want := map[int]string{0xff1: "新名称", 0xff2: "新系列"}; !reflect.DeepEqual(s.Setnames, want) {
+		t.Errorf("LoadStrings() setnames = %v, want %v", s.Setnames, want)
+	}
+
+	s.Use()
+	if got := ArchetypeName(0xff2); got != "新系列" {
+		t.Errorf("ArchetypeName() after Use() = %q", got)
+	}
+	if name, ok := CounterName(0xff1); !ok || name != "测试指示物" {
+		t.Errorf("CounterName() after Use() = %q, %v", name, ok)
+	}
+	if got := CountersIn("放置1个测试指示物"); len(got) != 1 || got[0].Code != 0xff1 {
+		t.Errorf("CountersIn() = %+v", got)
+	}
+
+	if err := os.WriteFile(second, []byte("!setname 0xff3\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadStrings(first, second); err == nil || !strings.Contains(err.Error(), second) {
+		t.Errorf("LoadStrings() error = %v, want the failing file", err)
+	}
+	if _, err := LoadStrings(filepath.Join(dir, "missing.conf")); err == nil {
+		t.Error("LoadStrings() of a missing file succeeded")
+	}
+}
@@ -0,0 +1,176 @@
+// Package cardinfo decodes the YGOPro bitmask fields of card data.
+package cardinfo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type is the YGOPro card type bitmask
+type Type int
+
+// Card type flags
+const (
+	TypeMonster     Type = 0x1
+	TypeSpell       Type = 0x2
+	TypeTrap        Type = 0x4
+	TypeNormal      Type = 0x10
+	TypeEffect      Type = 0x20
+	TypeFusion      Type = 0x40
+	TypeRitual      Type = 0x80
+	TypeTrapMonster Type = 0x100
+	TypeSpirit      Type = 0x200
+	TypeUnion       Type = 0x400
+	TypeGemini      Type = 0x800
+	TypeTuner       Type = 0x1000
+	TypeSynchro     Type = 0x2000
+	TypeToken       Type = 0x4000
+	TypeQuickPlay   Type = 0x10000
+	TypeContinuous  Type = 0x20000
+	TypeEquip       Type = 0x40000
+	TypeField       Type = 0x80000
+	TypeCounter     Type = 0x100000
+	TypeFlip        Type = 0x200000
+	TypeToon        Type = 0x400000
+	TypeXyz         Type = 0x800000
+	TypePendulum    Type = 0x1000000
+	TypeSpSummon    Type = 0x2000000
+	TypeLink        Type = 0x4000000
+
+	// typeExtraDeck are the types of monsters that belong in the extra deck
+	typeExtraDeck = TypeFusion | TypeSynchro | TypeXyz | TypeLink
+)
+
+// typeNames lists the card type flags in display order
+var typeNames = []struct {
+	flag Type
+	cn   string
+	en   string
+}{
+	{TypeMonster, "怪兽", "monster"},
+	{TypeSpell, "魔法", "spell"},
+	{TypeTrap, "陷阱", "trap"},
+	{TypeNormal, "通常", "normal"},
+	{TypeEffect, "效果", "effect"},
+	{TypeFusion, "融合", "fusion"},
+	{TypeRitual, "仪式", "ritual"},
+	{TypeTrapMonster, "陷阱怪兽", "trapmonster"},
+	{TypeSpirit, "灵魂", "spirit"},
+	{TypeUnion, "同盟", "union"},
+	{TypeGemini, "二重", "gemini"},
+	{TypeTuner, "调整", "tuner"},
+	{TypeSynchro, "同调", "synchro"},
+	{TypeToken, "衍生物", "token"},
+	{TypeQuickPlay, "速攻", "quickplay"},
+	{TypeContinuous, "永续", "continuous"},
+	{TypeEquip, "装备", "equip"},
+	{TypeField, "场地", "field"},
+	{TypeCounter, "反击", "counter"},
+	{TypeFlip, "反转", "flip"},
+	{TypeToon, "卡通", "toon"},
+	{TypeXyz, "超量", "xyz"},
+	{TypePendulum, "灵摆", "pendulum"},
+	{TypeSpSummon, "特殊召唤", "spsummon"},
+	{TypeLink, "连接", "link"},
+}
+
+// Has reports whether all of the given flags are set
+func (t Type) Has(flags Type) bool {
+	return t&flags == flags
+}
+
+// IsMonster reports whether the card is a monster
+func (t Type) IsMonster() bool {
+	return t.Has(TypeMonster)
+}
+
+// IsSpell reports whether the card is a spell
+func (t Type) IsSpell() bool {
+	return t.Has(TypeSpell)
+}
+
+// IsTrap reports whether the card is a trap
+func (t Type) IsTrap() bool {
+	return t.Has(TypeTrap)
+}
+
+// IsExtraDeck reports whether the card is a Fusion, Synchro, Xyz or Link
+// monster and thus belongs in the extra deck
+func (t Type) IsExtraDeck() bool {
+	return t.IsMonster() && t&typeExtraDeck != 0
+}
+
+// Frame returns the flag that determines the card frame: Normal, Effect,
+// Fusion, Ritual, Synchro, Xyz, Link or Token for monsters, Spell or Trap
+// otherwise. Pendulum monsters are framed by their other type, check
+// TypePendulum separately.
+func (t Type) Frame() Type {
+	switch {
+	case t.IsSpell():
+		return TypeSpell
+	case t.IsTrap():
+		return TypeTrap
+	}
+	for _, frame := range []Type{TypeLink, TypeXyz, TypeSynchro, TypeFusion, TypeRitual, TypeToken, TypeNormal} {
+		if t.Has(frame) {
+			return frame
+		}
+	}
+	return TypeEffect
+}
+
+// Flags returns the individual flags set in the bitmask in display order
+func (t Type) Flags() []Type {
+	var flags []Type
+	for _, name := range typeNames {
+		if t.Has(name.flag) {
+			flags = append(flags, name.flag)
+		}
+	}
+	return flags
+}
+
+// String returns the Chinese name of the type, e.g. "怪兽|效果|调整".
+// Spells and traps without a subtype are shown as "通常".
+func (t Type) String() string {
+	if t == 0 {
+		return "未知类型(0)"
+	}
+
+	var names []string
+	known := Type(0)
+	for _, name := range typeNames {
+		if t.Has(name.flag) {
+			names = append(names, name.cn)
+			known |= name.flag
+		}
+	}
+	if unknown := t &^ known; unknown != 0 {
+		names = append(names, fmt.Sprintf("未知类型(%#x)", int(unknown)))
+	}
+	if (t.IsSpell() || t.IsTrap()) && len(names) == 1 {
+		names = append(names, "通常")
+	}
+	return strings.Join(names, "|")
+}
+
+// TypeName returns the Chinese name of a single type flag
+func TypeName(flag Type) string {
+	for _, name := range typeNames {
+		if name.flag == flag {
+			return name.cn
+		}
+	}
+	return fmt.Sprintf("未知类型(%#x)", int(flag))
+}
+
+// ParseType returns the type flag with the given Chinese or English name
+func ParseType(name string) (Type, bool) {
+	name = strings.ToLower(name)
+	for _, n := range typeNames {
+		if n.cn == name || n.en == name {
+			return n.flag, true
+		}
+	}
+	return 0, false
+}
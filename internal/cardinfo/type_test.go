@@ -0,0 +1,159 @@
+package cardinfo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTypeString(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  Type
+		want string
+	}{
+		{"normal monster", 0x11, "怪兽|通常"},
+		{"synchro tuner effect", 0x3021, "怪兽|效果|调整|同调"},
+		{"link effect", 0x4000021, "怪兽|效果|连接"},
+		{"pendulum xyz", 0x1800021, "怪兽|效果|超量|灵摆"},
+		{"flip effect", 0x200021, "怪兽|效果|反转"},
+		{"token", 0x4011, "怪兽|通常|衍生物"},
+		{"normal spell", 0x2, "魔法|通常"},
+		{"ritual spell", 0x82, "魔法|仪式"},
+		{"quick-play spell", 0x10002, "魔法|速攻"},
+		{"field spell", 0x80002, "魔法|场地"},
+		{"normal trap", 0x4, "陷阱|通常"},
+		{"counter trap", 0x100004, "陷阱|反击"},
+		{"continuous trap", 0x20004, "陷阱|永续"},
+		{"unknown bit", 0x8000021, "怪兽|效果|未知类型(0x8000000)"},
+		{"zero", 0, "未知类型(0)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.typ.String(); got != tt.want {
+				t.Errorf("Type(%#x).String() = %q, want %q", int(tt.typ), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypePredicates(t *testing.T) {
+	tests := []struct {
+		name      string
+		typ       Type
+		frame     Type
+		extraDeck bool
+	}{
+		{"normal monster", 0x11, TypeNormal, false},
+		{"effect monster", 0x21, TypeEffect, false},
+		{"synchro tuner effect", 0x3021, TypeSynchro, true},
+		{"fusion", 0x41, TypeFusion, true},
+		{"ritual effect", 0xa1, TypeRitual, false},
+		{"xyz pendulum", 0x1800021, TypeXyz, true},
+		{"pendulum effect", 0x1000021, TypeEffect, false},
+		{"link", 0x4000021, TypeLink, true},
+		{"token", 0x4011, TypeToken, false},
+		{"ritual spell", 0x82, TypeSpell, false},
+		{"counter trap", 0x100004, TypeTrap, false},
+		// Trap monsters are traps until they are activated
+		{"trap monster", 0x104, TypeTrap, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.typ.Frame(); got != tt.frame {
+				t.Errorf("Type(%#x).Frame() = %s, want %s", int(tt.typ), TypeName(got), TypeName(tt.frame))
+			}
+			if got := tt.typ.IsExtraDeck(); got != tt.extraDeck {
+				t.Errorf("Type(%#x).IsExtraDeck() = %v, want %v", int(tt.typ), got, tt.extraDeck)
+			}
+		})
+	}
+
+	if got, want := Type(0x3021).Flags(), []Type{TypeMonster, TypeEffect, TypeTuner, TypeSynchro}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Flags() = %v, want %v", got, want)
+	}
+	if !Type(0x100004).IsTrap() || Type(0x100004).IsSpell() || Type(0x100004).IsMonster() {
+		t.Error("counter trap is not only a trap")
+	}
+}
+
+func TestParseType(t *testing.T) {
+	tests := []struct {
+		name string
+		want Type
+		ok   bool
+	}{
+		{"synchro", TypeSynchro, true},
+		{"Synchro", TypeSynchro, true},
+		{"同调", TypeSynchro, true},
+		{"quickplay", TypeQuickPlay, true},
+		{"反击", TypeCounter, true},
+		{"link", TypeLink, true},
+		{"unknown", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseType(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseType(%q) = %#x, %v, want %#x, %v", tt.name, int(got), ok, int(tt.want), tt.ok)
+		}
+	}
+	if got := TypeName(0x8000000); got != "未知类型(0x8000000)" {
+		t.Errorf("TypeName(unknown) = %q", got)
+	}
+}
+
+func TestRaceAndAttribute(t *testing.T) {
+	races := []struct {
+		race Race
+		want string
+	}{
+		{RaceWarrior, "战士"},
+		{RaceDragon, "龙"},
+		{RaceWyrm, "幻龙"},
+		{RaceCyberse, "电子界"},
+		{RaceIllusion, "幻想魔"},
+		{0x4000000, "未知种族(67108864)"},
+		{RaceWarrior | RaceDragon, "未知种族(8193)"},
+		{0, "未知种族(0)"},
+	}
+	for _, tt := range races {
+		if got := tt.race.String(); got != tt.want {
+			t.Errorf("Race(%#x).String() = %q, want %q", int(tt.race), got, tt.want)
+		}
+	}
+
+	attributes := []struct {
+		attribute Attribute
+		want      string
+	}{
+		{AttributeEarth, "地"},
+		{AttributeLight, "光"},
+		{AttributeDark, "暗"},
+		{AttributeDivine, "神"},
+		{0x80, "未知属性(128)"},
+		{0, "未知属性(0)"},
+	}
+	for _, tt := range attributes {
+		if got := tt.attribute.String(); got != tt.want {
+			t.Errorf("Attribute(%#x).String() = %q, want %q", int(tt.attribute), got, tt.want)
+		}
+	}
+
+	if race, ok := ParseRace("SpellCaster"); !ok || race != RaceSpellcaster {
+		t.Errorf("ParseRace(SpellCaster) = %#x, %v", int(race), ok)
+	}
+	if race, ok := ParseRace("幻龙"); !ok || race != RaceWyrm {
+		t.Errorf("ParseRace(幻龙) = %#x, %v", int(race), ok)
+	}
+	if _, ok := ParseRace("dragons"); ok {
+		t.Error("ParseRace(dragons) succeeded")
+	}
+	if attribute, ok := ParseAttribute("DARK"); !ok || attribute != AttributeDark {
+		t.Errorf("ParseAttribute(DARK) = %#x, %v", int(attribute), ok)
+	}
+	if attribute, ok := ParseAttribute("水"); !ok || attribute != AttributeWater {
+		t.Errorf("ParseAttribute(水) = %#x, %v", int(attribute), ok)
+	}
+	if _, ok := ParseAttribute("shadow"); ok {
+		t.Error("ParseAttribute(shadow) succeeded")
+	}
+}
@@ -0,0 +1,142 @@
+// Package cdb reads YGOPro card databases (cards.cdb).
+package cdb
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/cardinfo"
+	"ygocdb-tui/internal/log"
+
+	// Pure-Go SQLite driver, so the tool builds without cgo
+	_ "modernc.org/sqlite"
+)
+
+const (
+	// artworkOffset is the maximum distance between the ID of an alternative
+	// artwork and the ID of the card it is an alias of
+	artworkOffset = 20
+)
+
+// monsterTextMarkers separate the pendulum effect from the monster text in
+// the descriptions of Pendulum monsters, for the languages YGOPro ships
+var monsterTextMarkers = []string{
+	"【怪兽效果】",
+	"【怪兽描述】",
+	"【怪獸效果】",
+	"【怪獸描述】",
+	"【モンスター効果】",
+	"【モンスター情報】",
+	"[ Monster Effect ]",
+	"[ Flavor Text ]",
+	"----------------------------------------",
+}
+
+// cardsQuery selects every card joined with its texts
+const cardsQuery = `SELECT datas.id, datas.ot, datas.alias, datas.setcode, datas.type,
+	datas.atk, datas.def, datas.level, datas.race, datas.attribute,
+	texts.name, texts.desc
+FROM datas JOIN texts ON datas.id = texts.id
+ORDER BY datas.id`
+
+// Load reads the cards of one or more card databases. Cards from later
+// databases replace cards with the same ID from earlier ones, matching how
+// YGOPro clients load expansions. Alternative artworks are not returned as
+// cards but mapped to the passcode of their original artwork in aliases.
+func Load(paths ...string) (cards []api.Card, aliases map[int]int, err error) {
+	aliases = make(map[int]int)
+	for _, path := range paths {
+		loaded, err := loadFile(path, aliases)
+		if err != nil {
+			return nil, nil, err
+		}
+		cards = append(cards, loaded...)
+	}
+	return cards, aliases, nil
+}
+
+// loadFile reads the cards of a single card database, adding its
+// alternative artworks to aliases
+func loadFile(path string, aliases map[int]int) ([]api.Card, error) {
+	log.Info("Loading card database: %s", path)
+
+	dsn := (&url.URL{Scheme: "file", Path: path, RawQuery: "mode=ro"}).String()
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open card database %s: %w", path, err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query(cardsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read card database %s: %w", path, err)
+	}
+	defer rows.Close()
+
+	var cards []api.Card
+	for rows.Next() {
+		var (
+			card  api.Card
+			alias int
+		)
+		if err := rows.Scan(
+			&card.ID, &card.Data.OT, &alias, &card.Data.Setcode, &card.Data.Type,
+			&card.Data.Atk, &card.Data.Def, &card.Data.Level, &card.Data.Race, &card.Data.Attrib,
+			&card.Text.Name, &card.Text.Desc,
+		); err != nil {
+			return nil, fmt.Errorf("failed to read card database %s: %w", path, err)
+		}
+		if alias != 0 && card.ID-alias > -artworkOffset && card.ID-alias < artworkOffset {
+			log.Debug("Card %d is an alternative artwork of card %d", card.ID, alias)
+			aliases[card.ID] = alias
+			continue
+		}
+		card.CnName = card.Text.Name
+		if cardinfo.Type(card.Data.Type).Has(cardinfo.TypePendulum) {
+			card.Text.PDesc, card.Text.Desc = splitPendulumText(card.Text.Desc)
+		}
+		cards = append(cards, card)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read card database %s: %w", path, err)
+	}
+
+	log.Info("Loaded %d cards from %s", len(cards), path)
+	return cards, nil
+}
+
+// pendulumHeaders start the lines before the pendulum effect that hold the
+// scales or a section title
+var pendulumHeaders = []string{"←", "Pendulum Scale", "[ Pendulum Effect ]"}
+
+// isPendulumHeader reports whether text starts with a pendulum header line
+func isPendulumHeader(text string) bool {
+	for _, header := range pendulumHeaders {
+		if strings.HasPrefix(text, header) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitPendulumText splits the description of a Pendulum monster into its
+// pendulum effect and monster text. Descriptions in an unknown layout are
+// returned unchanged as the monster text.
+func splitPendulumText(desc string) (pdesc, text string) {
+	for _, marker := range monsterTextMarkers {
+		before, after, found := strings.Cut(desc, marker)
+		if !found {
+			continue
+		}
+		// Drop the scale header lines, the scales are decoded from the level,
+		// and the separator before the monster text
+		before = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(before), "-"))
+		for isPendulumHeader(before) {
+			_, before, _ = strings.Cut(before, "\n")
+		}
+		return strings.TrimSpace(before), strings.TrimSpace(after)
+	}
+	return "", desc
+}
@@ -0,0 +1,136 @@
+package cdb
+
+import (
+	"database/sql"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"ygocdb-tui/internal/cardinfo"
+)
+
+// testCard is a row of a test card database
+type testCard struct {
+	id, alias, typ, level int
+	name, desc            string
+}
+
+// writeCDB creates a card database in a temporary directory
+func writeCDB(t *testing.T, cards ...testCard) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cards.cdb")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	statements := []string{
+		`CREATE TABLE datas (id INTEGER PRIMARY KEY, ot INTEGER, alias INTEGER, setcode INTEGER, type INTEGER,
+			atk INTEGER, def INTEGER, level INTEGER, race INTEGER, attribute INTEGER, category INTEGER)`,
+		`CREATE TABLE texts (id INTEGER PRIMARY KEY, name TEXT, desc TEXT)`,
+	}
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, card := range cards {
+		if _, err := db.Exec(`INSERT INTO datas VALUES (?, 3, ?, 0, ?, 0, 0, ?, 0, 0, 0)`,
+			card.id, card.alias, card.typ, card.level); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec(`INSERT INTO texts VALUES (?, ?, ?)`, card.id, card.name, card.desc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	monster := int(cardinfo.TypeMonster | cardinfo.TypeEffect)
+	pendulum := int(cardinfo.TypeMonster | cardinfo.TypeEffect | cardinfo.TypePendulum)
+	first := writeCDB(t,
+		testCard{id: 46986414, typ: monster, name: "黑魔术师", desc: "魔法师"},
+		testCard{id: 46986415, alias: 46986414, typ: monster, name: "黑魔术师", desc: "魔法师"},
+		testCard{id: 46986433, alias: 46986414, typ: monster, name: "黑魔术师", desc: "魔法师"},
+		// Cards further away that are treated as another card are not artworks
+		testCard{id: 46986434, alias: 46986414, typ: monster, name: "黑魔术师", desc: "当作黑魔术师使用"},
+		testCard{id: 1000, alias: 46986414, typ: monster, name: "法老的仆人", desc: "当作黑魔术师使用"},
+		testCard{id: 16178681, typ: pendulum, level: 0x04040007, name: "异色眼灵摆龙",
+			desc: "←4 【灵摆】 4→\n灵摆效果\n【怪兽效果】\n怪兽效果"},
+	)
+	second := writeCDB(t, testCard{id: 46986414, typ: monster, name: "黑魔术师", desc: "新的卡片文本"})
+
+	cards, aliases, err := Load(first, second)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	var ids []int
+	for _, card := range cards {
+		ids = append(ids, card.ID)
+	}
+	if want := []int{1000, 16178681, 46986414, 46986434, 46986414}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Load() card IDs = %v, want %v", ids, want)
+	}
+	if want := map[int]int{46986415: 46986414, 46986433: 46986414}; !reflect.DeepEqual(aliases, want) {
+		t.Errorf("Load() aliases = %v, want %v", aliases, want)
+	}
+
+	if card := cards[0]; card.CnName != "法老的仆人" || card.Text.Name != "法老的仆人" || card.Data.OT != 3 {
+		t.Errorf("Load() card = %+v", card)
+	}
+	if card := cards[1]; card.Text.PDesc != "灵摆效果" || card.Text.Desc != "怪兽效果" || card.Data.Level != 0x04040007 {
+		t.Errorf("Load() pendulum card text = %q / %q, level %#x", card.Text.PDesc, card.Text.Desc, card.Data.Level)
+	}
+	if card := cards[4]; card.Text.Desc != "新的卡片文本" {
+		t.Errorf("Load() card of the later database desc = %q", card.Text.Desc)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	if _, _, err := Load(filepath.Join(t.TempDir(), "missing.cdb")); err == nil {
+		t.Error("Load() of a missing database succeeded")
+	}
+
+	// A database without the card tables
+	path := filepath.Join(t.TempDir(), "empty.cdb")
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`CREATE TABLE other (id INTEGER)`); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+	if _, _, err := Load(path); err == nil {
+		t.Error("Load() of a database without card tables succeeded")
+	}
+}
+
+func TestSplitPendulumText(t *testing.T) {
+	tests := []struct {
+		name      string
+		desc      string
+		wantPDesc string
+		wantText  string
+	}{
+		{"simplified Chinese", "←4 【灵摆】 4→\n①：灵摆效果。\n【怪兽效果】\n①：怪兽效果。", "①：灵摆效果。", "①：怪兽效果。"},
+		{"normal monster", "←8 【灵摆】 8→\n灵摆效果\n【怪兽描述】\n风味文本", "灵摆效果", "风味文本"},
+		{"Japanese", "←1 【ペンデュラム】 1→\nP効果\n【モンスター効果】\nモンスター効果", "P効果", "モンスター効果"},
+		{"English", "Pendulum Scale = 4\n[ Pendulum Effect ]\nP effect\n----------------------------------------\n[ Monster Effect ]\nMonster effect",
+			"P effect", "Monster effect"},
+		{"English without pendulum effect", "Pendulum Scale = 4\n----------------------------------------\n[ Flavor Text ]\nFlavor",
+			"", "Flavor"},
+		{"no pendulum effect", "←4 【灵摆】 4→\n【怪兽效果】\n怪兽效果", "", "怪兽效果"},
+		{"unknown layout", "效果文本", "", "效果文本"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pdesc, text := splitPendulumText(tt.desc)
+			if pdesc != tt.wantPDesc || text != tt.wantText {
+				t.Errorf("splitPendulumText() = %q, %q, want %q, %q", pdesc, text, tt.wantPDesc, tt.wantText)
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+// Package config loads the user configuration file, which sets the defaults
+// of the command line options and the look of the UI
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/cardinfo"
+	"ygocdb-tui/internal/format"
+	"ygocdb-tui/internal/log"
+	"ygocdb-tui/internal/paths"
+
+	"github.com/BurntSushi/toml"
+)
+
+// FileName is the name of the config file in the config directory
+const FileName = "config.toml"
+
+// Limits of the numeric settings
+const (
+	MaxPageSize       = 100
+	MaxInputCharLimit = 65536
+	MaxInputWidth     = 1000
+)
+
+// Config is the user configuration. Settings that are not given are zero and
+// keep their built-in defaults.
+type Config struct {
+	BaseURL    string        `toml:"base_url"`
+	Timeout    time.Duration `toml:"timeout"`
+	Retries    *int          `toml:"retries"`
+	CacheTTL   time.Duration `toml:"cache_ttl"`
+	Region     string        `toml:"region"`
+	Lang       string        `toml:"lang"`
+	Format     string        `toml:"format"`
+	CDB        []string      `toml:"cdb"`
+	Strings    []string      `toml:"strings"`
+	LFList     []string      `toml:"lflist"`
+	Banlist    string        `toml:"banlist"`
+	LogLevel   string        `toml:"log_level"`
+	LogDir     string        `toml:"log_dir"`
+	DatasetDir string        `toml:"dataset_dir"`
+	PageSize   int           `toml:"page_size"`
+	Input      Input         `toml:"input"`
+	Colors     Colors        `toml:"colors"`
+}
+
+// Input configures the search input
+type Input struct {
+	CharLimit int `toml:"char_limit"`
+	Width     int `toml:"width"`
+}
+
+// Colors configures the colors of the UI, as hex codes like "#7D56F4" or
+// ANSI color numbers from 0 to 255
+type Colors struct {
+	Title           string `toml:"title"`
+	TitleBackground string `toml:"title_background"`
+	Accent          string `toml:"accent"`
+	Help            string `toml:"help"`
+}
+
+// Path returns the path of the config file, e.g. ~/.config/ygocdb-tui/config.toml
+func Path() (string, error) {
+	dir, err := paths.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Load reads and validates the config file at path. A missing file is an
+// empty config.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	return Parse(path, data)
+}
+
+// Parse parses and validates the config file contents read from path
+func Parse(path string, data []byte) (*Config, error) {
+	var c Config
+	md, err := toml.Decode(string(data), &c)
+	if err != nil {
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("invalid config file %s: line %d: %s", path, parseErr.Position.Line, parseErr.Message)
+		}
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	var errs []error
+	for _, key := range md.Undecoded() {
+		errs = append(errs, fmt.Errorf("unknown setting %s", key))
+	}
+	errs = append(errs, c.validate(md)...)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid config file %s:\n  %w", path, joinErrors(errs))
+	}
+	return &c, nil
+}
+
+// validate returns an error for each invalid setting defined in md
+func (c *Config) validate(md toml.MetaData) []error {
+	var errs []error
+	invalid := func(key string, err error) {
+		errs = append(errs, fmt.Errorf("%s: %w", key, err))
+	}
+
+	if c.BaseURL != "" {
+		if u, err := url.Parse(c.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("base_url", fmt.Errorf("invalid URL %q (expected http:// or https://)", c.BaseURL))
+		}
+	}
+	if md.IsDefined("timeout") && c.Timeout <= 0 {
+		invalid("timeout", fmt.Errorf("must be positive, got %s", c.Timeout))
+	}
+	if c.Retries != nil && *c.Retries < 0 {
+		invalid("retries", fmt.Errorf("must not be negative, got %d", *c.Retries))
+	}
+	if c.CacheTTL < 0 {
+		invalid("cache_ttl", fmt.Errorf("must not be negative, got %s", c.CacheTTL))
+	}
+	if c.Region != "" {
+		if _, err := cardinfo.ParseRegion(c.Region); err != nil {
+			invalid("region", err)
+		}
+	}
+	if c.Lang != "" {
+		if _, err := api.ParseNameLang(c.Lang); err != nil {
+			invalid("lang", err)
+		}
+	}
+	if c.Format != "" {
+		if _, err := format.Lookup(c.Format); err != nil {
+			invalid("format", err)
+		}
+	}
+	if c.LogLevel != "" {
+		if _, err := log.ParseLevel(c.LogLevel); err != nil {
+			invalid("log_level", fmt.Errorf("%w (expected off, error, warn, info or debug)", err))
+		}
+	}
+	if md.IsDefined("page_size") && (c.PageSize < 1 || c.PageSize > MaxPageSize) {
+		invalid("page_size", fmt.Errorf("must be between 1 and %d, got %d", MaxPageSize, c.PageSize))
+	}
+	if md.IsDefined("input", "char_limit") && (c.Input.CharLimit < 1 || c.Input.CharLimit > MaxInputCharLimit) {
+		invalid("input.char_limit", fmt.Errorf("must be between 1 and %d, got %d", MaxInputCharLimit, c.Input.CharLimit))
+	}
+	if md.IsDefined("input", "width") && (c.Input.Width < 1 || c.Input.Width > MaxInputWidth) {
+		invalid("input.width", fmt.Errorf("must be between 1 and %d, got %d", MaxInputWidth, c.Input.Width))
+	}
+	colors := []struct {
+		key   string
+		color string
+	}{
+		{"colors.title", c.Colors.Title},
+		{"colors.title_background", c.Colors.TitleBackground},
+		{"colors.accent", c.Colors.Accent},
+		{"colors.help", c.Colors.Help},
+	}
+	for _, setting := range colors {
+		if setting.color != "" && !ValidColor(setting.color) {
+			invalid(setting.key, fmt.Errorf("invalid color %q (expected a hex code like \"#7D56F4\" or an ANSI color number from 0 to 255)", setting.color))
+		}
+	}
+	return errs
+}
+
+// hexColor matches hex color codes like "#7D56F4" and "#FFF"
+var hexColor = regexp.MustCompile(`^#([0-9A-Fa-f]{3}|[0-9A-Fa-f]{6})$`)
+
+// ValidColor reports whether color is a hex color code or an ANSI color number
+func ValidColor(color string) bool {
+	if hexColor.MatchString(color) {
+		return true
+	}
+	n, err := strconv.Atoi(color)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// joinErrors joins errors into one error with a line per error
+func joinErrors(errs []error) error {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return errors.New(strings.Join(messages, "\n  "))
+}
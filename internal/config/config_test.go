@@ -0,0 +1,131 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	data := `
+base_url = "https://example.com/api"
+timeout = "5s"
+retries = 0
+cache_ttl = "1h"
+region = "ocg"
+lang = "jp"
+format = "csv"
+cdb = ["/a.cdb", "/b.cdb"]
+page_size = 50
+
+[input]
+width = 60
+
+[colors]
+title = "#FFF"
+accent = "212"
+`
+	c, err := Parse("config.toml", []byte(data))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	retries := 0
+	want := &Config{
+		BaseURL:  "https://example.com/api",
+		Timeout:  5 * time.Second,
+		Retries:  &retries,
+		CacheTTL: time.Hour,
+		Region:   "ocg",
+		Lang:     "jp",
+		Format:   "csv",
+		CDB:      []string{"/a.cdb", "/b.cdb"},
+		PageSize: 50,
+		Input:    Input{Width: 60},
+		Colors:   Colors{Title: "#FFF", Accent: "212"},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("Parse() = %+v, want %+v", c, want)
+	}
+
+	if c, err := Parse("config.toml", nil); err != nil || !reflect.DeepEqual(c, &Config{}) {
+		t.Errorf("Parse() of an empty file = %+v, %v", c, err)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []string
+	}{
+		{"syntax error", "region = \"ocg\"\nlang = \n", []string{"config.toml: line 2"}},
+		{"wrong type", `page_size = "50"`, []string{"config.toml"}},
+		{"unknown key", "regoin = \"ocg\"\n[colors]\nbackground = \"#000\"", []string{
+			"unknown setting regoin", "unknown setting colors.background",
+		}},
+		{"invalid URL", `base_url = "ygocdb.com"`, []string{"base_url: invalid URL"}},
+		{"zero timeout", `timeout = "0s"`, []string{"timeout: must be positive"}},
+		{"negative retries", `retries = -1`, []string{"retries: must not be negative"}},
+		{"invalid region", `region = "asia"`, []string{"region: invalid region"}},
+		{"invalid language", `lang = "fr"`, []string{"lang:"}},
+		{"invalid format", `format = "xml"`, []string{"format: invalid format"}},
+		{"invalid log level", `log_level = "trace"`, []string{"log_level:"}},
+		{"page size too large", `page_size = 101`, []string{"page_size: must be between 1 and 100, got 101"}},
+		{"zero input width", "[input]\nwidth = 0", []string{"input.width: must be between 1 and 1000"}},
+		{"invalid color", "[colors]\nhelp = \"256\"", []string{`colors.help: invalid color "256"`}},
+		{"several errors", "timeout = \"-1s\"\nregion = \"asia\"\nfoo = 1", []string{
+			"unknown setting foo", "timeout: must be positive", "region: invalid region",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse("config.toml", []byte(tt.data))
+			if err == nil {
+				t.Fatal("Parse() succeeded")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Parse() error = %v, want %q", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if c, err := Load(filepath.Join(dir, "missing.toml")); err != nil || !reflect.DeepEqual(c, &Config{}) {
+		t.Errorf("Load() of a missing file = %+v, %v", c, err)
+	}
+
+	path := filepath.Join(dir, FileName)
+	if err := os.WriteFile(path, []byte(`region = "tcg"`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := Load(path); err != nil || c.Region != "tcg" {
+		t.Errorf("Load() = %+v, %v", c, err)
+	}
+}
+
+func TestValidColor(t *testing.T) {
+	tests := map[string]bool{
+		"#7D56F4": true,
+		"#fff":    true,
+		"0":       true,
+		"255":     true,
+		"256":     false,
+		"-1":      false,
+		"#7D56F":  false,
+		"7D56F4":  false,
+		"red":     false,
+		"":        false,
+	}
+	for color, want := range tests {
+		if got := ValidColor(color); got != want {
+			t.Errorf("ValidColor(%q) = %v, want %v", color, got, want)
+		}
+	}
+}
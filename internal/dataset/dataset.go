@@ -0,0 +1,206 @@
+// Package dataset mirrors the full ygocdb card dataset locally.
+package dataset
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/log"
+	"ygocdb-tui/internal/paths"
+)
+
+const (
+	// DumpPath is the API path of the zipped card dataset
+	DumpPath = "/api/v0/cards.zip"
+	// ChecksumPath is the API path of the MD5 checksum of the dataset
+	ChecksumPath = "/api/v0/cards.zip.md5"
+
+	// dumpEntry is the name of the card file inside the dataset archive
+	dumpEntry = "cards.json"
+	// cardsFile is the name of the local card file
+	cardsFile = "cards.json"
+	// metaFile is the name of the local metadata file
+	metaFile = "meta.json"
+)
+
+// ErrNotSynced is returned when no dataset has been synced yet
+var ErrNotSynced = errors.New("card dataset not synced")
+
+// Meta describes a synced dataset
+type Meta struct {
+	// Version is the MD5 checksum of the downloaded archive
+	Version  string    `json:"version"`
+	SyncedAt time.Time `json:"synced_at"`
+	Count    int       `json:"count"`
+}
+
+// Dir returns the default dataset directory
+func Dir() (string, error) {
+	dir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "dataset"), nil
+}
+
+// ReadMeta reads the metadata of the dataset in dir
+func ReadMeta(dir string) (*Meta, error) {
+	data, err := os.ReadFile(filepath.Join(dir, metaFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotSynced
+		}
+		return nil, fmt.Errorf("failed to read dataset metadata: %w", err)
+	}
+
+	var meta Meta
+	if err := json.Unmarshal(data, &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse dataset metadata: %w", err)
+	}
+	return &meta, nil
+}
+
+// Load reads the cards of the dataset in dir
+func Load(dir string) ([]api.Card, *Meta, error) {
+	log.Info("Loading card dataset from %s", dir)
+
+	meta, err := ReadMeta(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := os.ReadFile(filepath.Join(dir, cardsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, ErrNotSynced
+		}
+		return nil, nil, fmt.Errorf("failed to read card dataset: %w", err)
+	}
+
+	var cards []api.Card
+	if err := json.Unmarshal(data, &cards); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse card dataset: %w", err)
+	}
+
+	log.Info("Loaded %d cards, dataset version %s", len(cards), meta.Version)
+	return cards, meta, nil
+}
+
+// Sync downloads the dataset into dir unless the local copy is already up
+// to date. With force the dataset is downloaded regardless. It reports
+// whether the local dataset was updated.
+func Sync(ctx context.Context, client *api.Client, dir string, force bool) (*Meta, bool, error) {
+	log.Info("Syncing card dataset into %s", dir)
+
+	checksum, err := client.Download(ctx, ChecksumPath)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to download dataset checksum: %w", err)
+	}
+	version := parseChecksum(checksum)
+	if version == "" {
+		return nil, false, fmt.Errorf("%w: empty dataset checksum", api.ErrDecode)
+	}
+
+	if current, err := ReadMeta(dir); err == nil && current.Version == version && !force {
+		log.Info("Card dataset is up to date, version %s", version)
+		return current, false, nil
+	}
+
+	archive, err := client.Download(ctx, DumpPath)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to download dataset: %w", err)
+	}
+	sum := md5.Sum(archive)
+	if got := hex.EncodeToString(sum[:]); got != version {
+		return nil, false, fmt.Errorf("dataset checksum mismatch: expected %s, got %s", version, got)
+	}
+
+	cards, err := readArchive(archive)
+	if err != nil {
+		return nil, false, err
+	}
+
+	data, err := json.Marshal(cards)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode card dataset: %w", err)
+	}
+	meta := &Meta{Version: version, SyncedAt: time.Now(), Count: len(cards)}
+	metaData, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode dataset metadata: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, false, fmt.Errorf("failed to create dataset directory: %w", err)
+	}
+	// Write the metadata last so an interrupted sync is retried next time
+	if err := paths.WriteFile(dir, cardsFile, data); err != nil {
+		return nil, false, err
+	}
+	if err := paths.WriteFile(dir, metaFile, metaData); err != nil {
+		return nil, false, err
+	}
+
+	log.Info("Card dataset synced, version %s, %d cards", version, len(cards))
+	return meta, true, nil
+}
+
+// parseChecksum extracts the hex digest from an md5sum style checksum file
+func parseChecksum(data []byte) string {
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(fields[0])
+}
+
+// readArchive extracts the cards from the dataset archive, ordered by ID
+func readArchive(archive []byte) ([]api.Card, error) {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid dataset archive: %w", api.ErrDecode, err)
+	}
+
+	for _, file := range zr.File {
+		if file.Name != dumpEntry {
+			continue
+		}
+		rc, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s in dataset archive: %w", dumpEntry, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s in dataset archive: %w", dumpEntry, err)
+		}
+
+		// The dump is an object keyed by card, not an array
+		var byKey map[string]api.Card
+		if err := json.Unmarshal(data, &byKey); err != nil {
+			return nil, fmt.Errorf("%w: invalid card dataset: %w", api.ErrDecode, err)
+		}
+		cards := make([]api.Card, 0, len(byKey))
+		for _, card := range byKey {
+			if card.ID != 0 {
+				cards = append(cards, card)
+			}
+		}
+		sort.Slice(cards, func(i, j int) bool {
+			return cards[i].ID < cards[j].ID
+		})
+		return cards, nil
+	}
+	return nil, fmt.Errorf("%w: %s missing from dataset archive", api.ErrDecode, dumpEntry)
+}
@@ -0,0 +1,178 @@
+package dataset
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"ygocdb-tui/internal/api"
+)
+
+// dumpServer serves a dataset archive and its checksum
+type dumpServer struct {
+	*httptest.Server
+	archive   atomic.Pointer[[]byte]
+	checksum  atomic.Pointer[string]
+	downloads atomic.Int32
+}
+
+// newDumpServer starts a server serving archive with its MD5 checksum
+func newDumpServer(t *testing.T, archive []byte) *dumpServer {
+	s := &dumpServer{}
+	s.serve(archive, "")
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case ChecksumPath:
+			w.Write([]byte(*s.checksum.Load() + "  cards.zip\n"))
+		case DumpPath:
+			s.downloads.Add(1)
+			w.Write(*s.archive.Load())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// serve replaces the archive, announcing checksum or its actual MD5 sum
+func (s *dumpServer) serve(archive []byte, checksum string) {
+	if checksum == "" {
+		sum := md5.Sum(archive)
+		checksum = hex.EncodeToString(sum[:])
+	}
+	s.archive.Store(&archive)
+	s.checksum.Store(&checksum)
+}
+
+// makeArchive zips files into a dataset archive
+func makeArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+const testDump = `{
+	"灰流丽": {"id": 14558127, "cn_name": "灰流丽"},
+	"青眼白龙": {"id": 89631139, "cn_name": "青眼白龙"},
+	"empty": {}
+}`
+
+func TestSync(t *testing.T) {
+	s := newDumpServer(t, makeArchive(t, map[string]string{dumpEntry: testDump}))
+	client := api.NewClient(api.WithBaseURL(s.URL), api.WithRetry(0, 0))
+	dir := filepath.Join(t.TempDir(), "dataset")
+
+	if _, _, err := Load(dir); !errors.Is(err, ErrNotSynced) {
+		t.Fatalf("Load() before syncing error = %v, want ErrNotSynced", err)
+	}
+
+	meta, updated, err := Sync(context.Background(), client, dir, false)
+	if err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	if !updated || meta.Count != 2 || meta.Version != *s.checksum.Load() {
+		t.Errorf("Sync() = %+v, %v", meta, updated)
+	}
+
+	cards, loaded, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if len(cards) != 2 || cards[0].ID != 14558127 || cards[1].CnName != "青眼白龙" {
+		t.Errorf("Load() cards = %+v", cards)
+	}
+	if loaded.Version != meta.Version || loaded.Count != 2 {
+		t.Errorf("Load() meta = %+v, want %+v", loaded, meta)
+	}
+
+	// An up-to-date dataset is not downloaded again unless forced
+	if _, updated, err := Sync(context.Background(), client, dir, false); err != nil || updated {
+		t.Errorf("Sync() of an up-to-date dataset = %v, %v", updated, err)
+	}
+	if got := s.downloads.Load(); got != 1 {
+		t.Errorf("downloaded the archive %d times, want 1", got)
+	}
+	if _, updated, err := Sync(context.Background(), client, dir, true); err != nil || !updated {
+		t.Errorf("forced Sync() = %v, %v", updated, err)
+	}
+	if got := s.downloads.Load(); got != 2 {
+		t.Errorf("downloaded the archive %d times, want 2", got)
+	}
+}
+
+func TestSyncKeepsDatasetOnFailure(t *testing.T) {
+	s := newDumpServer(t, makeArchive(t, map[string]string{dumpEntry: testDump}))
+	client := api.NewClient(api.WithBaseURL(s.URL), api.WithRetry(0, 0))
+	dir := t.TempDir()
+	synced, _, err := Sync(context.Background(), client, dir, false)
+	if err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		archive  []byte
+		checksum string
+	}{
+		{"checksum mismatch", makeArchive(t, map[string]string{dumpEntry: `{}`}), "0123456789abcdef0123456789abcdef"},
+		{"missing card file", makeArchive(t, map[string]string{"other.json": `{}`}), ""},
+		{"invalid card file", makeArchive(t, map[string]string{dumpEntry: `[`}), ""},
+		{"invalid archive", []byte("not a zip"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.serve(tt.archive, tt.checksum)
+			if _, _, err := Sync(context.Background(), client, dir, false); err == nil {
+				t.Fatal("Sync() succeeded")
+			}
+			cards, meta, err := Load(dir)
+			if err != nil || len(cards) != 2 || meta.Version != synced.Version {
+				t.Errorf("Load() after a failed sync = %d cards, %+v, %v", len(cards), meta, err)
+			}
+		})
+	}
+}
+
+func TestLoadRequiresMetadata(t *testing.T) {
+	// The metadata is written last, so cards without it are an interrupted sync
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, cardsFile), []byte(`[]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := Load(dir); !errors.Is(err, ErrNotSynced) {
+		t.Errorf("Load() error = %v, want ErrNotSynced", err)
+	}
+}
+
+func TestParseChecksum(t *testing.T) {
+	tests := map[string]string{
+		"0123ABCD  cards.zip\n": "0123abcd",
+		"0123abcd":              "0123abcd",
+		"  \n":                  "",
+	}
+	for data, want := range tests {
+		if got := parseChecksum([]byte(data)); got != want {
+			t.Errorf("parseChecksum(%q) = %q, want %q", data, got, want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Package deck represents decks with main, extra and side zones.
+package deck
+
+import (
+	"errors"
+	"fmt"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/cardinfo"
+)
+
+const (
+	// MainMin is the minimum number of cards in the main deck
+	MainMin = 40
+	// MainMax is the maximum number of cards in the main deck
+	MainMax = 60
+	// ExtraMax is the maximum number of cards in the extra deck
+	ExtraMax = 15
+	// SideMax is the maximum number of cards in the side deck
+	SideMax = 15
+	// MaxCopies is the maximum number of copies of a card in a deck
+	MaxCopies = 3
+)
+
+var (
+	// ErrZoneFull is returned when a card is added to a full zone
+	ErrZoneFull = errors.New("卡组区域已满")
+	// ErrTooManyCopies is returned when a card is added more than MaxCopies times
+	ErrTooManyCopies = errors.New("同名卡最多放入 3 张")
+	// ErrWrongZone is returned when a card is added to a zone it cannot be in
+	ErrWrongZone = errors.New("卡片不能放入该区域")
+)
+
+// Zone is a part of a deck
+type Zone int
+
+const (
+	// ZoneMain is the main deck
+	ZoneMain Zone = iota
+	// ZoneExtra is the extra deck
+	ZoneExtra
+	// ZoneSide is the side deck
+	ZoneSide
+)
+
+// Zones lists the zones of a deck in order
+var Zones = []Zone{ZoneMain, ZoneExtra, ZoneSide}
+
+// String returns the Chinese name of the zone
+func (z Zone) String() string {
+	switch z {
+	case ZoneMain:
+		return "主卡组"
+	case ZoneExtra:
+		return "额外卡组"
+	case ZoneSide:
+		return "副卡组"
+	}
+	return fmt.Sprintf("未知区域(%d)", int(z))
+}
+
+// Max returns the maximum number of cards in the zone
+func (z Zone) Max() int {
+	switch z {
+	case ZoneMain:
+		return MainMax
+	case ZoneExtra:
+		return ExtraMax
+	}
+	return SideMax
+}
+
+// Deck is a deck of cards. Cards are kept as full snapshots so a saved deck
+// can be shown without looking its cards up again.
+type Deck struct {
+	Name  string     `json:"name"`
+	Main  []api.Card `json:"main"`
+	Extra []api.Card `json:"extra"`
+	Side  []api.Card `json:"side"`
+}
+
+// New creates an empty deck
+func New(name string) *Deck {
+	return &Deck{Name: name, Main: []api.Card{}, Extra: []api.Card{}, Side: []api.Card{}}
+}
+
+// Cards returns the cards in a zone
+func (d *Deck) Cards(zone Zone) []api.Card {
+	switch zone {
+	case ZoneMain:
+		return d.Main
+	case ZoneExtra:
+		return d.Extra
+	}
+	return d.Side
+}
+
+// Entry is a distinct card of a zone and the number of its copies in the zone
+type Entry struct {
+	Card  api.Card
+	Count int
+}
+
+// Entries returns the distinct cards of a zone in the order they were added
+func (d *Deck) Entries(zone Zone) []Entry {
+	var entries []Entry
+	index := map[int]int{}
+	for _, card := range d.Cards(zone) {
+		if i, ok := index[card.ID]; ok {
+			entries[i].Count++
+			continue
+		}
+		index[card.ID] = len(entries)
+		entries = append(entries, Entry{Card: card, Count: 1})
+	}
+	return entries
+}
+
+// Len returns the number of cards in the deck
+func (d *Deck) Len() int {
+	return len(d.Main) + len(d.Extra) + len(d.Side)
+}
+
+// Copies returns the number of copies of a card across all zones, counting
+// its alternative artworks as the same card
+func (d *Deck) Copies(id int) int {
+	n := 0
+	for _, zone := range Zones {
+		for _, card := range d.Cards(zone) {
+			if card.BaseID() == id {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// ZoneFor returns the zone a card is added to outside the side deck: the
+// extra deck for Fusion, Synchro, Xyz and Link monsters, the main deck
+// otherwise
+func ZoneFor(card *api.Card) Zone {
+	if cardinfo.Type(card.Data.Type).IsExtraDeck() {
+		return ZoneExtra
+	}
+	return ZoneMain
+}
+
+// Add adds a card to the side deck if side is set, or to the zone it
+// belongs in otherwise. It returns the zone the card was added to.
+func (d *Deck) Add(card api.Card, side bool) (Zone, error) {
+	zone := ZoneFor(&card)
+	if side {
+		zone = ZoneSide
+	}
+	return zone, d.AddTo(zone, card)
+}
+
+// AddTo adds a card to a zone, enforcing the zone sizes and copy limit
+func (d *Deck) AddTo(zone Zone, card api.Card) error {
+	if zone != ZoneSide && zone != ZoneFor(&card) {
+		return fmt.Errorf("%w: %s", ErrWrongZone, zone)
+	}
+	if len(d.Cards(zone)) >= zone.Max() {
+		return fmt.Errorf("%w: %s最多 %d 张", ErrZoneFull, zone, zone.Max())
+	}
+	if d.Copies(card.BaseID()) >= MaxCopies {
+		return ErrTooManyCopies
+	}
+	d.set(zone, append(d.Cards(zone), card))
+	return nil
+}
+
+// Remove removes the card at index from a zone
+func (d *Deck) Remove(zone Zone, index int) {
+	cards := d.Cards(zone)
+	if index < 0 || index >= len(cards) {
+		return
+	}
+	d.set(zone, append(cards[:index:index], cards[index+1:]...))
+}
+
+// SizeError reports whether the main deck is below its minimum size
+func (d *Deck) SizeError() error {
+	if len(d.Main) < MainMin {
+		return fmt.Errorf("主卡组至少需要 %d 张，当前 %d 张", MainMin, len(d.Main))
+	}
+	return nil
+}
+
+// set replaces the cards in a zone
+func (d *Deck) set(zone Zone, cards []api.Card) {
+	switch zone {
+	case ZoneMain:
+		d.Main = cards
+	case ZoneExtra:
+		d.Extra = cards
+	default:
+		d.Side = cards
+	}
+}
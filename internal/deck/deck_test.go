@@ -0,0 +1,30 @@
+package deck
+
+import (
+	"testing"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/cardinfo"
+)
+
+// testCard returns an OCG/TCG effect monster
+func testCard(id, alias int) api.Card {
+	return api.Card{ID: id, Alias: alias, CnName: "测试怪兽", Data: api.Data{
+		OT:   3,
+		Type: int(cardinfo.TypeMonster | cardinfo.TypeEffect),
+	}}
+}
+
+func TestAddCountsAlternativeArtworks(t *testing.T) {
+	d := New("")
+	for _, card := range []api.Card{testCard(1, 0), testCard(2, 1), testCard(2, 1)} {
+		if _, err := d.Add(card, false); err != nil {
+			t.Fatalf("Add(%d) error: %v", card.ID, err)
+		}
+	}
+	if _, err := d.Add(testCard(1, 0), false); err != ErrTooManyCopies {
+		t.Errorf("Add(fourth copy) error = %v, want ErrTooManyCopies", err)
+	}
+	if n := d.Copies(1); n != 3 {
+		t.Errorf("Copies(1) = %d, want 3", n)
+	}
+}
@@ -0,0 +1,126 @@
+package deck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"ygocdb-tui/internal/paths"
+)
+
+// fileExt is the extension of saved decks
+const fileExt = ".json"
+
+// ErrInvalidName is returned for deck names that cannot be used as file names
+var ErrInvalidName = errors.New("卡组名称不能为空，且不能包含 / \\ 或以 . 开头")
+
+// Dir returns the default directory decks are saved in
+func Dir() (string, error) {
+	dir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "decks"), nil
+}
+
+// ValidName reports whether a deck can be saved under name
+func ValidName(name string) bool {
+	return strings.TrimSpace(name) != "" && !strings.ContainsAny(name, `/\`) && !strings.HasPrefix(name, ".")
+}
+
+// List returns the names of the decks saved in dir, and the file names of
+// the .ydk deck files in it, sorted by name
+func List(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list decks: %w", err)
+	}
+	names := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if name, ok := strings.CutSuffix(entry.Name(), fileExt); ok && ValidName(name) {
+			names = append(names, name)
+		} else if strings.EqualFold(filepath.Ext(entry.Name()), YDKExt) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Load reads the deck saved in dir under name
+func Load(dir, name string) (*Deck, error) {
+	if !ValidName(name) {
+		return nil, ErrInvalidName
+	}
+	return ReadFile(filepath.Join(dir, name+fileExt))
+}
+
+// ReadFile reads a saved deck file. The deck is named after the file.
+func ReadFile(path string) (*Deck, error) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deck %s: %w", name, err)
+	}
+	d := New(name)
+	if err := json.Unmarshal(data, d); err != nil {
+		return nil, fmt.Errorf("failed to decode deck %s: %w", name, err)
+	}
+	d.Name = name
+	return d, nil
+}
+
+// Save writes the deck to dir under its name, replacing any deck saved
+// under the same name
+func Save(dir string, d *Deck) error {
+	if !ValidName(d.Name) {
+		return ErrInvalidName
+	}
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode deck %s: %w", d.Name, err)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create deck directory: %w", err)
+	}
+	return paths.WriteFile(dir, d.Name+fileExt, data)
+}
+
+// ReadYDKFile reads the passcodes of a .ydk deck file
+func ReadYDKFile(path string) (*Passcodes, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deck file: %w", err)
+	}
+	defer f.Close()
+	return ReadYDK(f)
+}
+
+// ExportYDK writes the deck to dir as a .ydk deck file named after the deck
+// and returns its path
+func ExportYDK(dir string, d *Deck) (string, error) {
+	if !ValidName(d.Name) {
+		return "", ErrInvalidName
+	}
+	var b strings.Builder
+	if err := WriteYDK(&b, d.Passcodes()); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create deck directory: %w", err)
+	}
+	name := d.Name + YDKExt
+	if err := paths.WriteFile(dir, name, []byte(b.String())); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
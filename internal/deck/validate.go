@@ -0,0 +1,126 @@
+package deck
+
+import (
+	"fmt"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/banlist"
+	"ygocdb-tui/internal/cardinfo"
+)
+
+// Rule is a deck construction rule a deck can violate
+type Rule int
+
+const (
+	// RuleSize is violated by zones with too few or too many cards
+	RuleSize Rule = iota
+	// RuleForbidden is violated by forbidden cards
+	RuleForbidden
+	// RuleLimit is violated by cards with more copies than allowed
+	RuleLimit
+	// RuleRegion is violated by cards not available in the region
+	RuleRegion
+	// RuleZone is violated by cards in a zone they cannot be in
+	RuleZone
+	// RuleUnknown is violated by cards that could not be looked up
+	RuleUnknown
+)
+
+// Violation is a broken deck construction rule
+type Violation struct {
+	Rule Rule
+	// Card is the offending card, if the violation concerns a card
+	Card *api.Card
+	// Message describes the violation in Chinese
+	Message string
+}
+
+// Rules are the rules a deck is validated against
+type Rules struct {
+	// Banlist limits the copies of cards; without it any card may be
+	// played MaxCopies times
+	Banlist *banlist.List
+	// Region is the card pool the deck is played in; anime and custom cards
+	// are rejected in every region
+	Region cardinfo.Region
+	// NameLang is the preferred language of card names in messages
+	NameLang api.NameLang
+}
+
+// Validate returns every rule the deck violates, in the order of the zones
+// and cards of the deck
+func Validate(d *Deck, rules Rules) []Violation {
+	var violations []Violation
+	report := func(rule Rule, card *api.Card, format string, args ...any) {
+		violations = append(violations, Violation{Rule: rule, Card: card, Message: fmt.Sprintf(format, args...)})
+	}
+	
+	// Zone sizes
+	if len(d.Main) < MainMin || len(d.Main) > MainMax {
+		report(RuleSize, nil, "%s需要 %d–%d 张，当前 %d 张", ZoneMain, MainMin, MainMax, len(d.Main))
+	}
+	for _, zone := range []Zone{ZoneExtra, ZoneSide} {
+		if n := len(d.Cards(zone)); n > zone.Max() {
+			report(RuleSize, nil, "%s最多 %d 张，当前 %d 张", zone, zone.Max(), n)
+		}
+	}
+	
+	// Cards, each reported once across zones
+	seen := map[int]bool{}
+	for _, zone := range Zones {
+		cards := d.Cards(zone)
+		for i := range cards {
+			card := &cards[i]
+			name := cardName(card, rules.NameLang)
+			typ := cardinfo.Type(card.Data.Type)
+			
+			if card.Data.Type == 0 {
+				if !seen[card.ID] {
+					report(RuleUnknown, card, "%s: 无法识别的卡片，无法检查", name)
+				}
+				seen[card.ID] = true
+				continue
+			}
+			if zone == ZoneMain && typ.IsExtraDeck() {
+				report(RuleZone, card, "%s: 融合/同调/超量/连接怪兽不能放入%s", name, ZoneMain)
+			}
+			if zone == ZoneExtra && !typ.IsExtraDeck() {
+				report(RuleZone, card, "%s: 只有融合/同调/超量/连接怪兽能放入%s", name, ZoneExtra)
+			}
+			// Alternative artworks count as their original card
+			id := card.BaseID()
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			
+			limit := MaxCopies
+			if rules.Banlist != nil {
+				limit = min(rules.Banlist.Limit(id), MaxCopies)
+			}
+			copies := d.Copies(id)
+			switch {
+			case limit <= 0:
+				report(RuleForbidden, card, "%s: 禁止卡", name)
+			case copies > limit:
+				report(RuleLimit, card, "%s: 最多 %d 张，当前 %d 张", name, limit, copies)
+			}
+			
+			ot := cardinfo.OT(card.Data.OT)
+			switch {
+			case ot.IsUnofficial():
+				report(RuleRegion, card, "%s: 动画/自制卡不能使用", name)
+			case !rules.Region.Allows(ot):
+				report(RuleRegion, card, "%s: 不能在 %s 使用 (%s)", name, rules.Region, ot.Badge())
+			}
+		}
+	}
+	return violations
+}
+
+// cardName returns the name of a card for violation messages
+func cardName(card *api.Card, lang api.NameLang) string {
+	if name := card.DisplayName(lang); name != "" {
+		return fmt.Sprintf("%s (%d)", name, card.ID)
+	}
+	return fmt.Sprintf("未知卡片 (%d)", card.ID)
+}
@@ -0,0 +1,51 @@
+package deck
+
+import (
+	"strings"
+	"testing"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/banlist"
+)
+
+// fillMain fills the main deck up to MainMin with distinct cards
+func fillMain(d *Deck) {
+	for id := 1000; len(d.Main) < MainMin; id++ {
+		d.Main = append(d.Main, testCard(id, 0))
+	}
+}
+
+func TestValidateAlternativeArtworks(t *testing.T) {
+	const original, artwork = 89631139, 89631140
+	lists, err := banlist.Parse(strings.NewReader("!test\n89631139 1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		cards []api.Card
+		rules Rules
+		want  []Rule
+	}{
+		{"artworks are legal", []api.Card{testCard(original, 0), testCard(artwork, original)}, Rules{}, nil},
+		{"artworks count as copies", []api.Card{
+			testCard(original, 0), testCard(original, 0), testCard(artwork, original), testCard(artwork, original),
+		}, Rules{}, []Rule{RuleLimit}},
+		{"artworks are limited as their original", []api.Card{testCard(artwork, original), testCard(original, 0)},
+			Rules{Banlist: lists[0]}, []Rule{RuleLimit}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New("")
+			d.Main = append(d.Main, tt.cards...)
+			fillMain(d)
+			var got []Rule
+			for _, v := range Validate(d, tt.rules) {
+				got = append(got, v.Rule)
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("Validate() rules = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
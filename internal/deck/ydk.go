@@ -0,0 +1,203 @@
+package deck
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/log"
+)
+
+// YDKExt is the extension of YGOPro deck files
+const YDKExt = ".ydk"
+
+// resolveWorkers limits the concurrent lookups when resolving passcodes
+const resolveWorkers = 4
+
+// Passcodes is the passcodes of the cards in each zone of a deck, as stored
+// in deck files and links
+type Passcodes struct {
+	Main  []int
+	Extra []int
+	Side  []int
+}
+
+// Passcodes returns the passcodes of the cards of the deck
+func (d *Deck) Passcodes() *Passcodes {
+	p := &Passcodes{}
+	for _, card := range d.Main {
+		p.Main = append(p.Main, card.ID)
+	}
+	for _, card := range d.Extra {
+		p.Extra = append(p.Extra, card.ID)
+	}
+	for _, card := range d.Side {
+		p.Side = append(p.Side, card.ID)
+	}
+	return p
+}
+
+// ReadYDK reads a YGOPro .ydk deck file. Cards before the first section
+// belong to the main deck, as in YGOPro.
+func ReadYDK(r io.Reader) (*Passcodes, error) {
+	p := &Passcodes{}
+	zone := &p.Main
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+		case strings.EqualFold(line, "#main"):
+			zone = &p.Main
+		case strings.EqualFold(line, "#extra"):
+			zone = &p.Extra
+		case strings.EqualFold(line, "!side"):
+			zone = &p.Side
+		case strings.HasPrefix(line, "#"), strings.HasPrefix(line, "!"):
+			// Comments such as "#created by ..."
+		default:
+			id, err := strconv.Atoi(line)
+			if err != nil || id <= 0 {
+				return nil, fmt.Errorf("invalid passcode %q on line %d", line, n)
+			}
+			*zone = append(*zone, id)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read deck file: %w", err)
+	}
+	return p, nil
+}
+
+// WriteYDK writes the passcodes as a YGOPro .ydk deck file
+func WriteYDK(w io.Writer, p *Passcodes) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString("#created by ygocdb-tui\n#main\n")
+	writeIDs(bw, p.Main)
+	bw.WriteString("#extra\n")
+	writeIDs(bw, p.Extra)
+	bw.WriteString("!side\n")
+	writeIDs(bw, p.Side)
+	return bw.Flush()
+}
+
+// writeIDs writes one passcode per line
+func writeIDs(w *bufio.Writer, ids []int) {
+	for _, id := range ids {
+		w.WriteString(strconv.Itoa(id))
+		w.WriteByte('\n')
+	}
+}
+
+// MissingError is returned when some passcodes of a deck cannot be resolved
+type MissingError struct {
+	IDs []int
+}
+
+// Error lists the passcodes that were not found
+func (e *MissingError) Error() string {
+	ids := make([]string, len(e.IDs))
+	for i, id := range e.IDs {
+		ids[i] = strconv.Itoa(id)
+	}
+	return fmt.Sprintf("%d 张卡片未找到: %s", len(e.IDs), strings.Join(ids, ", "))
+}
+
+// Resolve looks up the cards of the passcodes in source. Each distinct
+// passcode is looked up once. Cards that are not found are kept with only
+// their passcode and reported by a *MissingError along with the deck.
+func Resolve(ctx context.Context, source api.CardSource, name string, p *Passcodes) (*Deck, error) {
+	cards, missing, err := lookup(ctx, source, p)
+	if err != nil {
+		return nil, err
+	}
+	
+	d := New(name)
+	for _, id := range p.Main {
+		d.Main = append(d.Main, cards[id])
+	}
+	for _, id := range p.Extra {
+		d.Extra = append(d.Extra, cards[id])
+	}
+	for _, id := range p.Side {
+		d.Side = append(d.Side, cards[id])
+	}
+	if len(missing) > 0 {
+		return d, &MissingError{IDs: missing}
+	}
+	return d, nil
+}
+
+// lookup looks up each distinct passcode concurrently
+func lookup(ctx context.Context, source api.CardSource, p *Passcodes) (map[int]api.Card, []int, error) {
+	var ids []int
+	cards := map[int]api.Card{}
+	for _, zone := range [][]int{p.Main, p.Extra, p.Side} {
+		for _, id := range zone {
+			if _, ok := cards[id]; !ok {
+				cards[id] = api.Card{ID: id}
+				ids = append(ids, id)
+			}
+		}
+	}
+	log.Info("Resolving %d distinct passcodes", len(ids))
+	
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		missing  []int
+		firstErr error
+	)
+	sem := make(chan struct{}, resolveWorkers)
+	for _, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			card, err := source.GetCardByIDContext(ctx, id)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case errors.Is(err, api.ErrNotFound):
+				log.Warn("Card %d not found", id)
+				missing = append(missing, id)
+			case err != nil:
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+			default:
+				// Keep the passcode of an alternative artwork that the
+				// source answered with its original card
+				resolved := card.Card
+				if resolved.ID != id {
+					resolved.Alias = resolved.BaseID()
+					resolved.ID = id
+				}
+				cards[id] = resolved
+			}
+		}()
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+	
+	// Report missing cards in deck order
+	order := map[int]int{}
+	for i, id := range ids {
+		order[id] = i
+	}
+	sort.Slice(missing, func(i, j int) bool {
+		return order[missing[i]] < order[missing[j]]
+	})
+	return cards, missing, nil
+}
@@ -0,0 +1,125 @@
+package deck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"ygocdb-tui/internal/api"
+)
+
+// fakeSource serves cards by ID. Like the ygocdb API, it answers the
+// passcode of an alternative artwork with its original card.
+type fakeSource struct {
+	cards   map[int]api.Card
+	aliases map[int]int
+}
+
+func (s *fakeSource) SearchCardsContext(ctx context.Context, query string, start int) (*api.SearchResponse, error) {
+	return &api.SearchResponse{}, nil
+}
+
+func (s *fakeSource) GetCardByIDContext(ctx context.Context, cardID int) (*api.GetCardResponse, error) {
+	if alias, ok := s.aliases[cardID]; ok {
+		cardID = alias
+	}
+	card, ok := s.cards[cardID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", api.ErrNotFound, cardID)
+	}
+	return &api.GetCardResponse{Card: card}, nil
+}
+
+func TestResolve(t *testing.T) {
+	source := &fakeSource{
+		cards:   map[int]api.Card{1: testCard(1, 0), 2: testCard(2, 0)},
+		aliases: map[int]int{10: 1},
+	}
+	d, err := Resolve(context.Background(), source, "test", &Passcodes{
+		Main: []int{1, 10, 3},
+		Side: []int{2, 3},
+	})
+	var missing *MissingError
+	if !errors.As(err, &missing) {
+		t.Fatalf("Resolve() error = %v, want *MissingError", err)
+	}
+	if !reflect.DeepEqual(missing.IDs, []int{3}) {
+		t.Errorf("MissingError.IDs = %v, want [3]", missing.IDs)
+	}
+	if got := d.Passcodes(); !reflect.DeepEqual(got, &Passcodes{Main: []int{1, 10, 3}, Side: []int{2, 3}}) {
+		t.Errorf("Passcodes() = %+v, want the resolved passcodes", got)
+	}
+	if artwork := d.Main[1]; artwork.Alias != 1 || artwork.BaseID() != 1 {
+		t.Errorf("alternative artwork alias = %d, want 1", artwork.Alias)
+	}
+	if d.Main[0].Alias != 0 {
+		t.Errorf("original card alias = %d, want 0", d.Main[0].Alias)
+	}
+}
+
+func TestYDKRoundTrip(t *testing.T) {
+	tests := []*Passcodes{
+		{},
+		{Main: []int{14558127, 14558127, 89631139}},
+		{Main: []int{14558127}, Extra: []int{44508094}, Side: []int{89631140, 1}},
+	}
+	for _, p := range tests {
+		var b strings.Builder
+		if err := WriteYDK(&b, p); err != nil {
+			t.Fatalf("WriteYDK() error: %v", err)
+		}
+		got, err := ReadYDK(strings.NewReader(b.String()))
+		if err != nil {
+			t.Errorf("ReadYDK(%q) error: %v", b.String(), err)
+			continue
+		}
+		if !reflect.DeepEqual(got, p) {
+			t.Errorf("ReadYDK(%q) = %+v, want %+v", b.String(), got, p)
+		}
+	}
+}
+
+func TestReadYDK(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want *Passcodes
+	}{
+		{"no sections", "1\n2\n", &Passcodes{Main: []int{1, 2}}},
+		{"missing side section", "#created by YGOPro\n#main\n1\n#extra\n2\n", &Passcodes{Main: []int{1}, Extra: []int{2}}},
+		{"comments, blank lines and CRLF", "#main\r\n1\r\n\r\n#comment\r\n!other\r\n2\r\n#EXTRA\r\n3\r\n!Side\r\n4\r\n",
+			&Passcodes{Main: []int{1, 2}, Extra: []int{3}, Side: []int{4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadYDK(strings.NewReader(tt.file))
+			if err != nil {
+				t.Fatalf("ReadYDK() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadYDK() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadYDKInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"text", "#main\n1\nabc\n", `invalid passcode "abc" on line 3`},
+		{"negative passcode", "#main\n-1\n", `invalid passcode "-1" on line 2`},
+		{"zero passcode", "0\n", `invalid passcode "0" on line 1`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadYDK(strings.NewReader(tt.file)); err == nil || err.Error() != tt.want {
+				t.Errorf("ReadYDK() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package deck
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// YDKEPrefix is the scheme of ydke:// deck links
+const YDKEPrefix = "ydke://"
+
+// ErrInvalidYDKE is returned for malformed ydke:// deck links
+var ErrInvalidYDKE = errors.New("无效的 ydke:// 卡组链接")
+
+// IsYDKE reports whether s is a ydke:// deck link
+func IsYDKE(s string) bool {
+	return len(s) >= len(YDKEPrefix) && strings.EqualFold(s[:len(YDKEPrefix)], YDKEPrefix)
+}
+
+// ParseYDKE decodes a ydke:// deck link. The link holds the main, extra and
+// side deck as "!"-terminated base64 arrays of little-endian 32-bit passcodes.
+func ParseYDKE(link string) (*Passcodes, error) {
+	link = strings.TrimSpace(link)
+	if !IsYDKE(link) {
+		return nil, ErrInvalidYDKE
+	}
+	// Each zone is terminated by "!", so a complete link has a fourth part
+	parts := strings.Split(link[len(YDKEPrefix):], "!")
+	if len(parts) < 4 {
+		return nil, fmt.Errorf("%w: 缺少卡组区域", ErrInvalidYDKE)
+	}
+	
+	p := &Passcodes{}
+	for i, zone := range []*[]int{&p.Main, &p.Extra, &p.Side} {
+		data, err := base64.StdEncoding.DecodeString(parts[i])
+		if err != nil || len(data)%4 != 0 {
+			return nil, fmt.Errorf("%w: %s无法解码", ErrInvalidYDKE, Zones[i])
+		}
+		for j := 0; j < len(data); j += 4 {
+			*zone = append(*zone, int(binary.LittleEndian.Uint32(data[j:])))
+		}
+	}
+	return p, nil
+}
+
+// YDKE encodes the passcodes as a ydke:// deck link
+func (p *Passcodes) YDKE() string {
+	var b strings.Builder
+	b.WriteString(YDKEPrefix)
+	for _, zone := range [][]int{p.Main, p.Extra, p.Side} {
+		data := make([]byte, 4*len(zone))
+		for i, id := range zone {
+			binary.LittleEndian.PutUint32(data[4*i:], uint32(id))
+		}
+		b.WriteString(base64.StdEncoding.EncodeToString(data))
+		b.WriteByte('!')
+	}
+	return b.String()
+}
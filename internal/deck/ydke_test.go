@@ -0,0 +1,70 @@
+package deck
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestYDKERoundTrip(t *testing.T) {
+	tests := []*Passcodes{
+		{},
+		{Main: []int{14558127, 14558127, 89631139}},
+		{Main: []int{14558127}, Extra: []int{44508094}, Side: []int{89631140, 1}},
+	}
+	for _, p := range tests {
+		link := p.YDKE()
+		got, err := ParseYDKE(link)
+		if err != nil {
+			t.Errorf("ParseYDKE(%q) error: %v", link, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, p) {
+			t.Errorf("ParseYDKE(%q) = %+v, want %+v", link, got, p)
+		}
+	}
+}
+
+func TestParseYDKE(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+		want *Passcodes
+	}{
+		{"upper-case scheme and spaces", " YDKE://r2LeAA==!!! ", &Passcodes{Main: []int{14574255}}},
+		{"trailing data", "ydke://r2LeAA==!!!extra", &Passcodes{Main: []int{14574255}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseYDKE(tt.link)
+			if err != nil {
+				t.Fatalf("ParseYDKE(%q) error: %v", tt.link, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseYDKE(%q) = %+v, want %+v", tt.link, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseYDKEInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		link string
+	}{
+		{"not a link", "14558127"},
+		{"other scheme", "http://r2LeAA==!!!"},
+		{"missing section", "ydke://r2LeAA==!!"},
+		{"no sections", "ydke://r2LeAA=="},
+		{"malformed base64", "ydke://r2LeAA=!!!"},
+		{"invalid characters", "ydke://r2Le*A==!!!"},
+		{"partial passcode", "ydke://r2Le!!!"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if p, err := ParseYDKE(tt.link); !errors.Is(err, ErrInvalidYDKE) {
+				t.Errorf("ParseYDKE(%q) = %+v, %v, want ErrInvalidYDKE", tt.link, p, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+// Package favorites stores starred cards on disk.
+package favorites
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/paths"
+)
+
+// Favorite is a starred card. The card is kept as a full snapshot so it can
+// be shown offline.
+type Favorite struct {
+	Card    api.Card  `json:"card"`
+	Tags    []string  `json:"tags,omitempty"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// HasTag reports whether the favorite is tagged with tag, ignoring case
+func (f *Favorite) HasTag(tag string) bool {
+	for _, t := range f.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Order is the order favorites are listed in
+type Order int
+
+const (
+	// ByAdded lists the most recently starred cards first
+	ByAdded Order = iota
+	// ByName lists cards by name
+	ByName
+	// ByID lists cards by passcode
+	ByID
+)
+
+// String returns the Chinese name of the order
+func (o Order) String() string {
+	switch o {
+	case ByName:
+		return "名称"
+	case ByID:
+		return "卡片密码"
+	}
+	return "最近添加"
+}
+
+// Next returns the next order
+func (o Order) Next() Order {
+	return (o + 1) % (ByID + 1)
+}
+
+// Store holds the favorites saved in a file
+type Store struct {
+	path  string
+	items []Favorite
+}
+
+// Path returns the default favorites file
+func Path() (string, error) {
+	dir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "favorites.json"), nil
+}
+
+// Load reads the favorites saved in path. A missing file holds no favorites.
+func Load(path string) (*Store, error) {
+	s := &Store{path: path, items: []Favorite{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read favorites: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.items); err != nil {
+		return nil, fmt.Errorf("failed to decode favorites %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Len returns the number of favorites
+func (s *Store) Len() int {
+	return len(s.items)
+}
+
+// Has reports whether a card is starred
+func (s *Store) Has(id int) bool {
+	return s.index(id) >= 0
+}
+
+// Toggle stars a card, or unstars it if it is starred, and saves the
+// favorites. It reports whether the card is now starred.
+func (s *Store) Toggle(card api.Card) (bool, error) {
+	if i := s.index(card.ID); i >= 0 {
+		s.items = append(s.items[:i], s.items[i+1:]...)
+		return false, s.Save()
+	}
+	s.items = append(s.items, Favorite{Card: card, AddedAt: time.Now()})
+	return true, s.Save()
+}
+
+// SetTags replaces the tags of a starred card and saves the favorites
+func (s *Store) SetTags(id int, tags []string) error {
+	i := s.index(id)
+	if i < 0 {
+		return nil
+	}
+	s.items[i].Tags = tags
+	return s.Save()
+}
+
+// List returns the favorites tagged with tag, or all favorites if tag is
+// empty, in the given order. Names are compared in the given language.
+func (s *Store) List(tag string, order Order, lang api.NameLang) []Favorite {
+	list := []Favorite{}
+	for _, f := range s.items {
+		if tag == "" || f.HasTag(tag) {
+			list = append(list, f)
+		}
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		switch order {
+		case ByName:
+			return list[i].Card.DisplayName(lang) < list[j].Card.DisplayName(lang)
+		case ByID:
+			return list[i].Card.ID < list[j].Card.ID
+		}
+		return list[i].AddedAt.After(list[j].AddedAt)
+	})
+	return list
+}
+
+// Tags returns every tag in use, sorted
+func (s *Store) Tags() []string {
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, f := range s.items {
+		for _, tag := range f.Tags {
+			if key := strings.ToLower(tag); !seen[key] {
+				seen[key] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Save writes the favorites to their file
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s.items, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode favorites: %w", err)
+	}
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create favorites directory: %w", err)
+	}
+	return paths.WriteFile(dir, filepath.Base(s.path), data)
+}
+
+// Export writes favorites as a JSON array
+func Export(w io.Writer, list []Favorite) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(list)
+}
+
+// ParseTags splits a list of tags separated by commas or whitespace
+func ParseTags(input string) []string {
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || r == '，' || r == ' ' || r == '\t' || r == '　'
+	})
+	var tags []string
+	seen := map[string]bool{}
+	for _, tag := range fields {
+		if key := strings.ToLower(tag); !seen[key] {
+			seen[key] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// index returns the index of a starred card, or -1
+func (s *Store) index(id int) int {
+	for i, f := range s.items {
+		if f.Card.ID == id {
+			return i
+		}
+	}
+	return -1
+}
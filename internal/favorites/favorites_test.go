@@ -0,0 +1,131 @@
+package favorites
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+	"ygocdb-tui/internal/api"
+)
+
+// ids returns the passcodes of the favorites
+func ids(list []Favorite) []int {
+	ids := []int{}
+	for _, f := range list {
+		ids = append(ids, f.Card.ID)
+	}
+	return ids
+}
+
+// toggle stars or unstars a card and checks the result
+func toggle(t *testing.T, s *Store, card api.Card, want bool) {
+	t.Helper()
+	starred, err := s.Toggle(card)
+	if err != nil {
+		t.Fatalf("Toggle(%d) error: %v", card.ID, err)
+	}
+	if starred != want {
+		t.Fatalf("Toggle(%d) = %v, want %v", card.ID, starred, want)
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "favorites.json")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of a missing file error: %v", err)
+	}
+	if s.Len() != 0 {
+		t.Fatalf("Load() of a missing file has %d favorites", s.Len())
+	}
+
+	cards := []api.Card{
+		{ID: 14558127, CnName: "灰流丽"},
+		{ID: 23434538, CnName: "增殖的G"},
+		{ID: 89631139, CnName: "青眼白龙"},
+	}
+	for _, card := range cards {
+		toggle(t, s, card, true)
+	}
+	// Space out the times so that the order does not depend on the clock
+	for i := range s.items {
+		s.items[i].AddedAt = time.Date(2024, 1, i+1, 0, 0, 0, 0, time.UTC)
+	}
+	if err := s.SetTags(14558127, []string{"手坑", "Staple"}); err != nil {
+		t.Fatalf("SetTags() error: %v", err)
+	}
+	if err := s.SetTags(23434538, []string{"staple"}); err != nil {
+		t.Fatalf("SetTags() error: %v", err)
+	}
+	if err := s.SetTags(1, []string{"ignored"}); err != nil {
+		t.Fatalf("SetTags() of an unstarred card error: %v", err)
+	}
+
+	s, err = Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if s.Len() != 3 || !s.Has(89631139) || s.Has(1) {
+		t.Fatalf("Load() = %v, want the saved favorites", ids(s.List("", ByID, api.NameCN)))
+	}
+	if want := []string{"Staple", "手坑"}; !reflect.DeepEqual(s.Tags(), want) {
+		t.Errorf("Tags() = %v, want %v", s.Tags(), want)
+	}
+
+	tests := []struct {
+		name  string
+		tag   string
+		order Order
+		want  []int
+	}{
+		{"most recent first", "", ByAdded, []int{89631139, 23434538, 14558127}},
+		{"by name", "", ByName, []int{23434538, 14558127, 89631139}},
+		{"by passcode", "", ByID, []int{14558127, 23434538, 89631139}},
+		{"tag ignoring case", "STAPLE", ByID, []int{14558127, 23434538}},
+		{"unused tag", "未使用", ByID, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ids(s.List(tt.tag, tt.order, api.NameCN)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("List(%q, %v) = %v, want %v", tt.tag, tt.order, got, tt.want)
+			}
+		})
+	}
+
+	toggle(t, s, cards[0], false)
+	s, err = Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if got := ids(s.List("", ByID, api.NameCN)); !reflect.DeepEqual(got, []int{23434538, 89631139}) {
+		t.Errorf("List() after unstarring = %v", got)
+	}
+	if want := []string{"staple"}; !reflect.DeepEqual(s.Tags(), want) {
+		t.Errorf("Tags() after unstarring = %v, want %v", s.Tags(), want)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "favorites.json")
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() of an invalid file succeeded")
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	tests := map[string][]string{
+		"":                      nil,
+		"手坑":                    {"手坑"},
+		"手坑, 泛用，Staple\tstaple": {"手坑", "泛用", "Staple"},
+		"　手坑　　":                 {"手坑"},
+	}
+	for input, want := range tests {
+		if got := ParseTags(input); !reflect.DeepEqual(got, want) {
+			t.Errorf("ParseTags(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Package format writes cards in machine-readable output formats for the
+// command line and for exports from the TUI
+package format
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"ygocdb-tui/internal/api"
+)
+
+// Format is a named output format
+type Format struct {
+	// Name identifies the format, as in -format=name
+	Name string
+	// Ext is the file extension of exported files, with the dot
+	Ext string
+	// Write writes the cards to w, naming them in lang where the format
+	// has a single name column
+	Write func(w io.Writer, cards []api.Card, lang api.NameLang) error
+}
+
+// formats lists the registered formats in registration order
+var formats []*Format
+
+// Register adds a format to the registry, replacing a format of the same name
+func Register(f *Format) {
+	for i, existing := range formats {
+		if existing.Name == f.Name {
+			formats[i] = f
+			return
+		}
+	}
+	formats = append(formats, f)
+}
+
+// Formats returns the registered formats in registration order
+func Formats() []*Format {
+	return formats
+}
+
+// Names returns the names of the registered formats
+func Names() []string {
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// Lookup returns the registered format of a name
+func Lookup(name string) (*Format, error) {
+	for _, f := range formats {
+		if strings.EqualFold(f.Name, name) {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid format: %s (expected %s)", name, strings.Join(Names(), ", "))
+}
+
+// Next returns the registered format after f, cycling through all formats
+func Next(f *Format) *Format {
+	for i, existing := range formats {
+		if existing == f {
+			return formats[(i+1)%len(formats)]
+		}
+	}
+	return formats[0]
+}
+
+func init() {
+	Register(&Format{Name: "json", Ext: ".json", Write: writeJSON})
+	Register(&Format{Name: "ndjson", Ext: ".ndjson", Write: writeNDJSON})
+	Register(&Format{Name: "csv", Ext: ".csv", Write: writeCSV})
+	Register(&Format{Name: "tsv", Ext: ".tsv", Write: writeTSV})
+	Register(&Format{Name: "markdown", Ext: ".md", Write: writeMarkdown})
+}
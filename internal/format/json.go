@@ -0,0 +1,31 @@
+package format
+
+import (
+	"encoding/json"
+	"io"
+	"ygocdb-tui/internal/api"
+)
+
+// writeJSON writes the cards as an indented JSON array of api.Card, which
+// decodes back to the same cards
+func writeJSON(w io.Writer, cards []api.Card, lang api.NameLang) error {
+	if cards == nil {
+		cards = []api.Card{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(cards)
+}
+
+// writeNDJSON writes the cards as one JSON object per line
+func writeNDJSON(w io.Writer, cards []api.Card, lang api.NameLang) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	for _, card := range cards {
+		if err := enc.Encode(card); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package format
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/cardinfo"
+)
+
+// columns are the flattened fields of a card in CSV and TSV output
+var columns = []string{
+	"id", "name", "jp_name", "en_name", "ot", "type", "race", "attribute",
+	"level", "left_scale", "right_scale", "atk", "def", "link_markers",
+	"archetypes", "pdesc", "desc",
+}
+
+// row flattens a card into the values of columns. Fields that do not apply
+// to the card, such as the ATK of a spell, are empty.
+func row(card *api.Card, lang api.NameLang) []string {
+	typ := cardinfo.Type(card.Data.Type)
+	var race, attribute, level, leftScale, rightScale, atk, def, markers string
+	if typ.IsMonster() {
+		stats := cardinfo.DecodeStats(typ, card.Data.Level, card.Data.Atk, card.Data.Def)
+		race = cardinfo.Race(card.Data.Race).String()
+		attribute = cardinfo.Attribute(card.Data.Attrib).String()
+		level = strconv.Itoa(stats.Level)
+		if typ.Has(cardinfo.TypePendulum) {
+			leftScale = strconv.Itoa(stats.LeftScale)
+			rightScale = strconv.Itoa(stats.RightScale)
+		}
+		atk = cardinfo.FormatStat(stats.Atk)
+		if typ.Has(cardinfo.TypeLink) {
+			markers = formatLinkMarkers(stats.LinkMarkers)
+		} else {
+			def = cardinfo.FormatStat(stats.Def)
+		}
+	}
+
+	codes := cardinfo.Setcodes(card.Data.Setcode)
+	archetypes := make([]string, len(codes))
+	for i, code := range codes {
+		archetypes[i] = cardinfo.ArchetypeName(code)
+	}
+
+	return []string{
+		strconv.Itoa(card.ID),
+		card.DisplayName(lang),
+		card.JpName,
+		card.EnName,
+		cardinfo.OT(card.Data.OT).Badge(),
+		typ.String(),
+		race,
+		attribute,
+		level,
+		leftScale,
+		rightScale,
+		atk,
+		def,
+		markers,
+		strings.Join(archetypes, "/"),
+		card.Text.PDesc,
+		card.Text.Desc,
+	}
+}
+
+// linkMarkerNames are the short names of the link markers, clockwise from
+// the top left
+var linkMarkerNames = []struct {
+	marker cardinfo.LinkMarker
+	name   string
+}{
+	{cardinfo.LinkTopLeft, "TL"},
+	{cardinfo.LinkTop, "T"},
+	{cardinfo.LinkTopRight, "TR"},
+	{cardinfo.LinkRight, "R"},
+	{cardinfo.LinkBottomRight, "BR"},
+	{cardinfo.LinkBottom, "B"},
+	{cardinfo.LinkBottomLeft, "BL"},
+	{cardinfo.LinkLeft, "L"},
+}
+
+// formatLinkMarkers formats link markers as short names separated by "/"
+func formatLinkMarkers(markers cardinfo.LinkMarker) string {
+	var names []string
+	for _, m := range linkMarkerNames {
+		if markers.Has(m.marker) {
+			names = append(names, m.name)
+		}
+	}
+	return strings.Join(names, "/")
+}
+
+// writeCSV writes the cards as RFC 4180 CSV with a header row
+func writeCSV(w io.Writer, cards []api.Card, lang api.NameLang) error {
+	cw := csv.NewWriter(w)
+	cw.Write(columns)
+	for i := range cards {
+		cw.Write(row(&cards[i], lang))
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// tsvEscaper escapes the characters that delimit TSV fields and rows
+var tsvEscaper = strings.NewReplacer("\\", "\\\\", "\t", "\\t", "\r\n", "\\n", "\n", "\\n", "\r", "\\n")
+
+// writeTSV writes the cards as tab-separated values with a header row. Tabs,
+// line breaks and backslashes in fields are escaped as \t, \n and \\.
+func writeTSV(w io.Writer, cards []api.Card, lang api.NameLang) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(strings.Join(columns, "\t") + "\n")
+	for i := range cards {
+		fields := row(&cards[i], lang)
+		for j, field := range fields {
+			fields[j] = tsvEscaper.Replace(field)
+		}
+		bw.WriteString(strings.Join(fields, "\t") + "\n")
+	}
+	return bw.Flush()
+}
+
+// markdownEscaper escapes the characters that break Markdown table cells
+var markdownEscaper = strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>", "\r", "<br>")
+
+// markdownColumns are the columns of Markdown output and their headers.
+// Numeric columns are right-aligned.
+var markdownColumns = []struct {
+	name    string
+	header  string
+	numeric bool
+}{
+	{"id", "卡片密码", true},
+	{"name", "名称", false},
+	{"type", "类型", false},
+	{"race", "种族", false},
+	{"attribute", "属性", false},
+	{"level", "等级", true},
+	{"atk", "攻击力", true},
+	{"def", "守备力", true},
+}
+
+// columnIndex returns the index of the named column in rows
+func columnIndex(name string) int {
+	i := slices.Index(columns, name)
+	if i < 0 {
+		panic("format: unknown column " + name)
+	}
+	return i
+}
+
+// writeMarkdown writes the cards as a Markdown table without the card text,
+// to be pasted into wikis and chats
+func writeMarkdown(w io.Writer, cards []api.Card, lang api.NameLang) error {
+	indexes := make([]int, len(markdownColumns))
+	headers := make([]string, len(markdownColumns))
+	aligns := make([]string, len(markdownColumns))
+	for i, column := range markdownColumns {
+		indexes[i] = columnIndex(column.name)
+		headers[i] = column.header
+		aligns[i] = "---"
+		if column.numeric {
+			aligns[i] = "---:"
+		}
+	}
+	
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "| %s |\n", strings.Join(headers, " | "))
+	fmt.Fprintf(bw, "| %s |\n", strings.Join(aligns, " | "))
+	cells := make([]string, len(markdownColumns))
+	for i := range cards {
+		r := row(&cards[i], lang)
+		for j, index := range indexes {
+			cells[j] = markdownEscaper.Replace(r[index])
+		}
+		fmt.Fprintf(bw, "| %s |\n", strings.Join(cells, " | "))
+	}
+	return bw.Flush()
+}
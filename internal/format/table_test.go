@@ -0,0 +1,43 @@
+package format
+
+import (
+	"strings"
+	"testing"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/cardinfo"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	cards := []api.Card{
+		{ID: 89631139, CnName: "青眼白龙", Data: api.Data{
+			Type:   int(cardinfo.TypeMonster | cardinfo.TypeNormal),
+			Race:   int(cardinfo.RaceDragon),
+			Attrib: int(cardinfo.AttributeLight),
+			Level:  8,
+			Atk:    3000,
+			Def:    2500,
+		}},
+		{ID: 1, CnName: "A|B", Data: api.Data{Type: int(cardinfo.TypeSpell)}},
+	}
+	var b strings.Builder
+	if err := writeMarkdown(&b, cards, api.NameCN); err != nil {
+		t.Fatal(err)
+	}
+	
+	want := "| 卡片密码 | 名称 | 类型 | 种族 | 属性 | 等级 | 攻击力 | 守备力 |\n" +
+		"| ---: | --- | --- | --- | --- | ---: | ---: | ---: |\n" +
+		"| 89631139 | 青眼白龙 | 怪兽\\|通常 | 龙 | 光 | 8 | 3000 | 2500 |\n" +
+		"| 1 | A\\|B | 魔法\\|通常 |  |  |  |  |  |\n"
+	if got := b.String(); got != want {
+		t.Errorf("writeMarkdown() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRowMatchesColumns(t *testing.T) {
+	if got := len(row(&api.Card{}, api.NameCN)); got != len(columns) {
+		t.Errorf("row() has %d values, want %d columns", got, len(columns))
+	}
+	for _, column := range markdownColumns {
+		columnIndex(column.name)
+	}
+}
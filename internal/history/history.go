@@ -0,0 +1,188 @@
+// Package history stores the search history on disk.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"ygocdb-tui/internal/paths"
+)
+
+// MaxEntries is the number of distinct queries kept
+const MaxEntries = 500
+
+// Entry is a query that was searched for
+type Entry struct {
+	Query string `json:"query"`
+	// Count is the number of results of the last search, or -1 if unknown
+	Count int `json:"count"`
+	// More reports whether the search had more results than Count
+	More     bool      `json:"more,omitempty"`
+	Uses     int       `json:"uses"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// Store holds the search history saved in a file, most recent first
+type Store struct {
+	path    string
+	entries []Entry
+}
+
+// Path returns the default history file
+func Path() (string, error) {
+	dir, err := paths.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.json"), nil
+}
+
+// Load reads the history saved in path. A missing file holds no history.
+func Load(path string) (*Store, error) {
+	s := &Store{path: path, entries: []Entry{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read search history: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.entries); err != nil {
+		return nil, fmt.Errorf("failed to decode search history %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Entries returns the history, most recent first
+func (s *Store) Entries() []Entry {
+	return s.entries
+}
+
+// Len returns the number of entries
+func (s *Store) Len() int {
+	return len(s.entries)
+}
+
+// Add records a search for query, moving it to the front of the history,
+// and saves the history
+func (s *Store) Add(query string) error {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+	entry := Entry{Query: query, Count: -1}
+	if i := s.index(query); i >= 0 {
+		entry = s.entries[i]
+		s.entries = append(s.entries[:i], s.entries[i+1:]...)
+	}
+	entry.Uses++
+	entry.LastUsed = time.Now()
+	s.entries = append([]Entry{entry}, s.entries...)
+	if len(s.entries) > MaxEntries {
+		s.entries = s.entries[:MaxEntries]
+	}
+	return s.Save()
+}
+
+// SetCount records the number of results of the last search for query and
+// saves the history
+func (s *Store) SetCount(query string, count int, more bool) error {
+	i := s.index(strings.TrimSpace(query))
+	if i < 0 {
+		return nil
+	}
+	s.entries[i].Count = count
+	s.entries[i].More = more
+	return s.Save()
+}
+
+// Remove deletes query from the history and saves the history
+func (s *Store) Remove(query string) error {
+	i := s.index(query)
+	if i < 0 {
+		return nil
+	}
+	s.entries = append(s.entries[:i], s.entries[i+1:]...)
+	return s.Save()
+}
+
+// Save writes the history to its file
+func (s *Store) Save() error {
+	data, err := json.MarshalIndent(s.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode search history: %w", err)
+	}
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	return paths.WriteFile(dir, filepath.Base(s.path), data)
+}
+
+// Filter returns the entries fuzzily matching pattern, best matches first.
+// Every character of the pattern must appear in the query in order; closer
+// and earlier matches rank higher. An empty pattern matches every entry.
+func Filter(entries []Entry, pattern string) []Entry {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" {
+		return entries
+	}
+	
+	type match struct {
+		entry Entry
+		score int
+	}
+	var matches []match
+	for _, entry := range entries {
+		if score, ok := fuzzyScore(strings.ToLower(entry.Query), pattern); ok {
+			matches = append(matches, match{entry, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score < matches[j].score
+	})
+	
+	filtered := make([]Entry, len(matches))
+	for i, m := range matches {
+		filtered[i] = m.entry
+	}
+	return filtered
+}
+
+// fuzzyScore matches pattern as a subsequence of text. The score counts the
+// skipped characters before and between matched characters; lower is better.
+func fuzzyScore(text, pattern string) (int, bool) {
+	want := []rune(pattern)
+	score, matched, last := 0, 0, -1
+	for i, r := range []rune(text) {
+		if matched == len(want) {
+			break
+		}
+		if r != want[matched] {
+			continue
+		}
+		if last >= 0 {
+			score += i - last - 1
+		} else {
+			score += i
+		}
+		last = i
+		matched++
+	}
+	return score, matched == len(want)
+}
+
+// index returns the index of query in the history, or -1
+func (s *Store) index(query string) int {
+	for i, entry := range s.entries {
+		if entry.Query == query {
+			return i
+		}
+	}
+	return -1
+}
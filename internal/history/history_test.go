@@ -0,0 +1,125 @@
+package history
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// queries returns the queries of the entries
+func queries(entries []Entry) []string {
+	queries := []string{}
+	for _, entry := range entries {
+		queries = append(queries, entry.Query)
+	}
+	return queries
+}
+
+// load reads the history saved in path
+func load(t *testing.T, path string) *Store {
+	t.Helper()
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	return s
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "history.json")
+	s := load(t, path)
+	if s.Len() != 0 {
+		t.Fatalf("Load() of a missing file has %d entries", s.Len())
+	}
+
+	for _, query := range []string{"灰流丽", "  ", "增殖的G", " 灰流丽 ", "青眼白龙"} {
+		if err := s.Add(query); err != nil {
+			t.Fatalf("Add(%q) error: %v", query, err)
+		}
+	}
+	if err := s.SetCount("灰流丽 ", 3, true); err != nil {
+		t.Fatalf("SetCount() error: %v", err)
+	}
+	if err := s.SetCount("未搜索", 1, false); err != nil {
+		t.Fatalf("SetCount() of an unknown query error: %v", err)
+	}
+
+	s = load(t, path)
+	if want := []string{"青眼白龙", "灰流丽", "增殖的G"}; !reflect.DeepEqual(queries(s.Entries()), want) {
+		t.Fatalf("Entries() = %q, want %q", queries(s.Entries()), want)
+	}
+	if got := s.Entries()[1]; got.Uses != 2 || got.Count != 3 || !got.More {
+		t.Errorf("repeated entry = %+v, want 2 uses and 3+ results", got)
+	}
+	if got := s.Entries()[0]; got.Uses != 1 || got.Count != -1 || got.LastUsed.IsZero() {
+		t.Errorf("new entry = %+v, want 1 use and an unknown count", got)
+	}
+
+	if err := s.Remove("灰流丽"); err != nil {
+		t.Fatalf("Remove() error: %v", err)
+	}
+	if err := s.Remove("未搜索"); err != nil {
+		t.Fatalf("Remove() of an unknown query error: %v", err)
+	}
+	s = load(t, path)
+	if want := []string{"青眼白龙", "增殖的G"}; !reflect.DeepEqual(queries(s.Entries()), want) {
+		t.Errorf("Entries() after Remove() = %q, want %q", queries(s.Entries()), want)
+	}
+}
+
+func TestAddKeepsMaxEntries(t *testing.T) {
+	s := &Store{path: filepath.Join(t.TempDir(), "history.json")}
+	for i := MaxEntries; i > 0; i-- {
+		s.entries = append(s.entries, Entry{Query: fmt.Sprint(i), Count: -1})
+	}
+	if err := s.Add("new"); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+	if s.Len() != MaxEntries {
+		t.Fatalf("Len() = %d, want %d", s.Len(), MaxEntries)
+	}
+	if first, last := s.Entries()[0].Query, s.Entries()[MaxEntries-1].Query; first != "new" || last != "2" {
+		t.Errorf("Entries() run from %q to %q, want the oldest entry dropped", first, last)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	if err := os.WriteFile(path, []byte("["), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("Load() of an invalid file succeeded")
+	}
+}
+
+func TestFilter(t *testing.T) {
+	entries := []Entry{
+		{Query: "atk>=2500"},
+		{Query: "Blue-Eyes"},
+		{Query: "青眼白龙"},
+		{Query: "blue eyes white dragon"},
+		{Query: "BE"},
+	}
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"", []string{"atk>=2500", "Blue-Eyes", "青眼白龙", "blue eyes white dragon", "BE"}},
+		{"  ", []string{"atk>=2500", "Blue-Eyes", "青眼白龙", "blue eyes white dragon", "BE"}},
+		// Ignores case, closer matches first and keeps the order of ties
+		{"be", []string{"BE", "Blue-Eyes", "blue eyes white dragon"}},
+		{"bew", []string{"blue eyes white dragon"}},
+		{"白龙", []string{"青眼白龙"}},
+		{"青白", []string{"青眼白龙"}},
+		{"白青", []string{}},
+		{"2500", []string{"atk>=2500"}},
+	}
+	for _, tt := range tests {
+		if got := queries(Filter(entries, tt.pattern)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Filter(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
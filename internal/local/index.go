@@ -0,0 +1,287 @@
+package local
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"ygocdb-tui/internal/api"
+)
+
+const (
+	// nameWeight is the weight of a token occurring in a card name
+	nameWeight = 3.0
+	// textWeight is the weight of a token occurring in the card text
+	textWeight = 1.0
+	// exactNameBoost is added to cards whose name equals the query
+	exactNameBoost = 100.0
+	// prefixNameBoost is added to cards whose name starts with the query
+	prefixNameBoost = 10.0
+	// saturation controls how quickly repeated occurrences stop adding weight
+	saturation = 1.2
+	// prefixWeight scales the weight of words that a query word is a prefix of
+	prefixWeight = 0.5
+)
+
+// posting records the occurrence of a token in a card
+type posting struct {
+	doc    int32
+	weight float32
+}
+
+// document holds the normalized searchable text of a card
+type document struct {
+	names []string
+	text  string
+}
+
+// Index is an inverted index over card names and effect text. CJK text is
+// indexed as unigrams and bigrams, other text as lower-cased words.
+type Index struct {
+	postings map[string][]posting
+	words    []string // Sorted non-CJK tokens, for prefix matching
+	docs     []document
+}
+
+// hit represents a card matching a query
+type hit struct {
+	doc   int
+	score float64
+}
+
+// NewIndex builds an index over cards
+func NewIndex(cards []api.Card) *Index {
+	idx := &Index{
+		postings: make(map[string][]posting),
+		docs:     make([]document, len(cards)),
+	}
+
+	weights := make(map[string]float64)
+	for i := range cards {
+		card := &cards[i]
+		doc := &idx.docs[i]
+		for _, name := range cardNames(card) {
+			if name = normalize(name); name != "" {
+				doc.names = append(doc.names, name)
+			}
+		}
+		doc.text = normalize(card.Text.PDesc + "\n" + card.Text.Desc)
+
+		clear(weights)
+		for _, name := range doc.names {
+			for _, token := range tokenize(name) {
+				weights[token] += nameWeight
+			}
+		}
+		for _, token := range tokenize(doc.text) {
+			weights[token] += textWeight
+		}
+		for token, weight := range weights {
+			// Saturate repeated occurrences so long texts do not dominate
+			saturated := weight * (saturation + 1) / (weight + saturation)
+			idx.postings[token] = append(idx.postings[token], posting{doc: int32(i), weight: float32(saturated)})
+		}
+	}
+
+	for token := range idx.postings {
+		if !isCJK([]rune(token)[0]) {
+			idx.words = append(idx.words, token)
+		}
+	}
+	sort.Strings(idx.words)
+	return idx
+}
+
+// lookup returns the postings of a query token. CJK tokens match exactly;
+// words also match the longer words they are a prefix of, so that "drag"
+// finds "dragon", with a lower weight than exact matches.
+func (idx *Index) lookup(token string) []posting {
+	if isCJK([]rune(token)[0]) {
+		return idx.postings[token]
+	}
+
+	weights := make(map[int32]float32)
+	for i := sort.SearchStrings(idx.words, token); i < len(idx.words) && strings.HasPrefix(idx.words[i], token); i++ {
+		scale := float32(prefixWeight)
+		if idx.words[i] == token {
+			scale = 1
+		}
+		for _, p := range idx.postings[idx.words[i]] {
+			weights[p.doc] = max(weights[p.doc], p.weight*scale)
+		}
+	}
+	list := make([]posting, 0, len(weights))
+	for doc, weight := range weights {
+		list = append(list, posting{doc: doc, weight: weight})
+	}
+	return list
+}
+
+// Search returns the cards matching query ordered by relevance. Every term
+// of the query must match; terms in double quotes must match as a phrase.
+func (idx *Index) Search(query string) []hit {
+	terms, phrases := parseQuery(query)
+	if len(terms) == 0 && len(phrases) == 0 {
+		return nil
+	}
+
+	var tokens []string
+	for _, term := range append(terms, phrases...) {
+		tokens = append(tokens, tokenize(normalize(term))...)
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	// Intersect postings starting from the rarest token
+	lists := make([][]posting, len(tokens))
+	for i, token := range tokens {
+		lists[i] = idx.lookup(token)
+	}
+	sort.Slice(lists, func(i, j int) bool {
+		return len(lists[i]) < len(lists[j])
+	})
+	scores := make(map[int32]float64)
+	for i, list := range lists {
+		if len(list) == 0 {
+			return nil
+		}
+		idf := math.Log(1 + float64(len(idx.docs))/float64(len(list)))
+		next := make(map[int32]float64, len(scores))
+		for _, p := range list {
+			score, ok := scores[p.doc]
+			if i > 0 && !ok {
+				continue
+			}
+			next[p.doc] = score + idf*float64(p.weight)
+		}
+		scores = next
+		if len(scores) == 0 {
+			return nil
+		}
+	}
+
+	whole := normalize(strings.Join(append(terms, phrases...), " "))
+	var hits []hit
+	for doc, score := range scores {
+		d := &idx.docs[doc]
+		if !d.containsPhrases(phrases) {
+			continue
+		}
+		for _, name := range d.names {
+			if name == whole {
+				score += exactNameBoost
+				break
+			} else if strings.HasPrefix(name, whole) {
+				score += prefixNameBoost
+				break
+			}
+		}
+		hits = append(hits, hit{doc: int(doc), score: score})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return hits[i].doc < hits[j].doc
+	})
+	return hits
+}
+
+// containsPhrases reports whether the document contains every phrase
+func (d *document) containsPhrases(phrases []string) bool {
+	for _, phrase := range phrases {
+		phrase = normalize(phrase)
+		found := strings.Contains(d.text, phrase)
+		for _, name := range d.names {
+			if found {
+				break
+			}
+			found = strings.Contains(name, phrase)
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// parseQuery splits a query into bare terms and double-quoted phrases
+func parseQuery(query string) (terms, phrases []string) {
+	for i, part := range strings.Split(query, "\"") {
+		if i%2 == 1 {
+			if part = strings.TrimSpace(part); part != "" {
+				phrases = append(phrases, part)
+			}
+			continue
+		}
+		terms = append(terms, strings.Fields(part)...)
+	}
+	return terms, phrases
+}
+
+// normalize folds full-width characters to their ASCII forms and katakana
+// to hiragana, lower-cases and collapses whitespace
+func normalize(s string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range s {
+		switch {
+		case r == '　':
+			r = ' '
+		case r >= '！' && r <= '～':
+			r -= 0xFEE0
+		case r >= 'ァ' && r <= 'ヶ':
+			// Katakana matches hiragana, so either kana finds a reading
+			r -= 0x60
+		}
+		if unicode.IsSpace(r) {
+			space = b.Len() > 0
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// isCJK reports whether r is a Chinese, Japanese or Korean character
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) || r == 'ー'
+}
+
+// tokenize splits normalized text into index tokens. Runs of CJK characters
+// yield their unigrams and bigrams; letters and digits yield words.
+func tokenize(text string) []string {
+	var tokens []string
+	var word []rune
+	var prev rune
+	flush := func() {
+		if len(word) > 0 {
+			tokens = append(tokens, string(word))
+			word = word[:0]
+		}
+	}
+	for _, r := range text {
+		switch {
+		case isCJK(r):
+			flush()
+			tokens = append(tokens, string(r))
+			if prev != 0 {
+				tokens = append(tokens, string([]rune{prev, r}))
+			}
+			prev = r
+			continue
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			word = append(word, r)
+		default:
+			flush()
+		}
+		prev = 0
+	}
+	flush()
+	return tokens
+}
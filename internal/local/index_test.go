@@ -0,0 +1,101 @@
+package local
+
+import (
+	"reflect"
+	"testing"
+	"ygocdb-tui/internal/api"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"灰流丽", []string{"灰", "流", "灰流", "丽", "流丽"}},
+		{"blue-eyes white dragon", []string{"blue", "eyes", "white", "dragon"}},
+		{"青眼の白龍", []string{"青", "眼", "青眼", "の", "眼の", "白", "の白", "龍", "白龍"}},
+		{"no.39 希望皇", []string{"no", "39", "希", "望", "希望", "皇", "望皇"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := tokenize(tt.text); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("tokenize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		s    string
+		want string
+	}{
+		{"Blue-Eyes  White\tDragon", "blue-eyes white dragon"},
+		{"ＡＢＣ１２３", "abc123"},
+		{"ブルーアイズ", "ぶるーあいず"},
+		{"　灰流丽　", "灰流丽"},
+	}
+	for _, tt := range tests {
+		if got := normalize(tt.s); got != tt.want {
+			t.Errorf("normalize(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestIndexSearch(t *testing.T) {
+	cards := []api.Card{
+		{ID: 1, CnName: "青眼白龙", EnName: "Blue-Eyes White Dragon", JpRuby: "ブルーアイズ・ホワイト・ドラゴン",
+			Text: api.Text{Desc: "以高攻击力著称的传说之龙。"}},
+		{ID: 2, CnName: "灰流丽", EnName: "Ash Blossom & Joyous Spring",
+			Text: api.Text{Desc: "这张卡从手卡送去墓地才能发动。那个效果无效。"}},
+		{ID: 3, CnName: "真红眼黑龙", EnName: "Red-Eyes Black Dragon",
+			Text: api.Text{Desc: "以攻击力著称的龙。"}},
+		{ID: 4, CnName: "龙之灵庙", EnName: "Dragon Shrine",
+			Text: api.Text{Desc: "从卡组把1只龙族怪兽送去墓地。"}},
+	}
+	idx := NewIndex(cards)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []int
+	}{
+		{"CJK unigram", "丽", []int{2}},
+		{"CJK bigram", "白龙", []int{1}},
+		{"CJK words in any order", "黑龙 真红", []int{3}},
+		{"CJK not found", "黑魔导", nil},
+		{"exact word", "dragon", []int{4, 1, 3}},
+		{"word prefix", "drag", []int{4, 1, 3}},
+		{"hyphenated prefix", "blue-eye", []int{1}},
+		{"every word must match", "ash spring", []int{2}},
+		{"prefix not inside a word", "ragon", nil},
+		{"full-width query", "ＡＳＨ", []int{2}},
+		{"quoted phrase", "\"送去墓地\"", []int{2, 4}},
+		{"quoted phrase in order", "\"墓地送去\"", nil},
+		{"phrase with term", "\"送去墓地\" 龙族", []int{4}},
+		{"katakana reading", "ホワイト", []int{1}},
+		{"hiragana finds katakana", "ほわいと", []int{1}},
+		{"empty", "  ", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for _, h := range idx.Search(tt.query) {
+				got = append(got, cards[h.doc].ID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndexSearchRanksExactWordsFirst(t *testing.T) {
+	cards := []api.Card{
+		{ID: 1, EnName: "Dragonmaid Lorpar"},
+		{ID: 2, EnName: "Dragon"},
+	}
+	hits := NewIndex(cards).Search("dragon")
+	if len(hits) != 2 || cards[hits[0].doc].ID != 2 {
+		t.Fatalf("Search(\"dragon\") ranked %v, want card 2 first", hits)
+	}
+}
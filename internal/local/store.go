@@ -0,0 +1,163 @@
+package local
+
+import (
+	"context"
+	"fmt"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/cardinfo"
+	"ygocdb-tui/internal/log"
+)
+
+const (
+	// SearchPageSize is the number of cards returned per search request
+	SearchPageSize = 30
+)
+
+// Store serves cards held in memory. It backs the offline card sources such
+// as YGOPro card databases.
+type Store struct {
+	cards   []api.Card
+	byID    map[int]int // Index into cards by card ID
+	aliases map[int]int // Original card IDs by alternative artwork ID
+	index   *Index
+}
+
+// Ensure Store satisfies the card source interfaces
+var (
+	_ api.CardSource      = (*Store)(nil)
+	_ api.ArchetypeSource = (*Store)(nil)
+	_ api.FilterSource    = (*Store)(nil)
+)
+
+// NewStore creates a store serving the given cards. Later cards replace
+// earlier ones with the same ID. aliases maps the IDs of alternative
+// artworks to the IDs of their original cards and may be nil.
+func NewStore(cards []api.Card, aliases map[int]int) *Store {
+	s := &Store{
+		cards:   make([]api.Card, 0, len(cards)),
+		byID:    make(map[int]int, len(cards)),
+		aliases: aliases,
+	}
+	for _, card := range cards {
+		if i, ok := s.byID[card.ID]; ok {
+			s.cards[i] = card
+			continue
+		}
+		s.byID[card.ID] = len(s.cards)
+		s.cards = append(s.cards, card)
+	}
+	s.index = NewIndex(s.cards)
+
+	log.Info("Local store created with %d cards", len(s.cards))
+	return s
+}
+
+// Len returns the number of cards in the store
+func (s *Store) Len() int {
+	return len(s.cards)
+}
+
+// Cards returns all cards in the store. The returned slice must not be modified.
+func (s *Store) Cards() []api.Card {
+	return s.cards
+}
+
+// SearchCardsContext searches card names and effect text for query with
+// pagination. Double-quoted parts of the query are matched as phrases.
+func (s *Store) SearchCardsContext(ctx context.Context, query string, start int) (*api.SearchResponse, error) {
+	log.Info("Searching local store with query: %s, start: %d", query, start)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	hits := s.index.Search(query)
+
+	resp := &api.SearchResponse{Result: []api.Card{}}
+	if start < 0 {
+		start = 0
+	}
+	for i := start; i < len(hits) && i < start+SearchPageSize; i++ {
+		resp.Result = append(resp.Result, s.cards[hits[i].doc])
+	}
+	if start+SearchPageSize < len(hits) {
+		resp.Next = start + SearchPageSize
+	}
+
+	log.Info("Local search completed, found %d matches", len(hits))
+	return resp, nil
+}
+
+// GetCardByIDContext gets a card by its ID
+func (s *Store) GetCardByIDContext(ctx context.Context, cardID int) (*api.GetCardResponse, error) {
+	log.Info("Getting card from local store by ID: %d", cardID)
+
+	card, ok := s.Card(cardID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", api.ErrNotFound, cardID)
+	}
+	return cardResponse(card), nil
+}
+
+// CardsByArchetype returns the cards belonging to the archetype code
+func (s *Store) CardsByArchetype(ctx context.Context, archetype int) ([]api.Card, error) {
+	log.Info("Listing cards of archetype %#x from local store", archetype)
+
+	return s.FilterCards(ctx, func(card *api.Card) bool {
+		return cardinfo.IsArchetype(card.Data.Setcode, archetype)
+	})
+}
+
+// FilterCards returns the cards for which match reports true
+func (s *Store) FilterCards(ctx context.Context, match func(*api.Card) bool) ([]api.Card, error) {
+	cards := []api.Card{}
+	for i := range s.cards {
+		if i%1024 == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if match(&s.cards[i]) {
+			cards = append(cards, s.cards[i])
+		}
+	}
+	return cards, nil
+}
+
+// Card returns the card with the given ID. An alternative artwork is
+// returned as its original card with the artwork's ID and Alias set.
+func (s *Store) Card(cardID int) (api.Card, bool) {
+	if i, ok := s.byID[cardID]; ok {
+		return s.cards[i], true
+	}
+	alias, ok := s.aliases[cardID]
+	if !ok {
+		return api.Card{}, false
+	}
+	i, ok := s.byID[alias]
+	if !ok {
+		return api.Card{}, false
+	}
+	card := s.cards[i]
+	card.ID = cardID
+	card.Alias = alias
+	return card, true
+}
+
+// cardResponse converts a card to the response of the get card API
+func cardResponse(card api.Card) *api.GetCardResponse {
+	return &api.GetCardResponse{Card: card}
+}
+
+// cardNames returns every name field of the card, and the reading of its
+// Japanese name
+func cardNames(card *api.Card) []string {
+	return []string{
+		card.Text.Name,
+		card.CnName,
+		card.ScName,
+		card.MdName,
+		card.NwbbsN,
+		card.CnocgN,
+		card.JpName,
+		cardinfo.Reading(card.JpRuby),
+		card.EnName,
+	}
+}
@@ -0,0 +1,32 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"ygocdb-tui/internal/api"
+)
+
+func TestStoreAliases(t *testing.T) {
+	s := NewStore([]api.Card{
+		{ID: 89631139, CnName: "青眼白龙"},
+	}, map[int]int{89631140: 89631139, 5: 6})
+
+	card, err := s.GetCardByIDContext(context.Background(), 89631140)
+	if err != nil {
+		t.Fatalf("GetCardByIDContext(alternative artwork) error: %v", err)
+	}
+	if card.ID != 89631140 || card.Alias != 89631139 || card.CnName != "青眼白龙" || card.BaseID() != 89631139 {
+		t.Errorf("GetCardByIDContext(alternative artwork) = %d alias %d %q, want 89631140 alias 89631139 青眼白龙", card.ID, card.Alias, card.CnName)
+	}
+
+	card, err = s.GetCardByIDContext(context.Background(), 89631139)
+	if err != nil || card.ID != 89631139 || card.Alias != 0 {
+		t.Errorf("GetCardByIDContext(original) = %+v, %v", card, err)
+	}
+
+	// An alias of a card that is not in the store is not found
+	if _, err := s.GetCardByIDContext(context.Background(), 5); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("GetCardByIDContext(dangling alias) error = %v, want ErrNotFound", err)
+	}
+}
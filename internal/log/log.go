@@ -5,10 +5,15 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 )
 
+// DefaultDir is the directory log files are written to by default, relative
+// to the working directory
+const DefaultDir = "logs"
+
 // LogLevel represents the severity level of a log message
 type LogLevel int
 
@@ -43,6 +48,24 @@ func (l LogLevel) String() string {
 	}
 }
 
+// ParseLevel parses a log level name: off, error, warn, info or debug
+func ParseLevel(name string) (LogLevel, error) {
+	switch strings.ToLower(name) {
+	case "off":
+		return OffLevel, nil
+	case "error":
+		return ErrorLevel, nil
+	case "warn":
+		return WarnLevel, nil
+	case "info":
+		return InfoLevel, nil
+	case "debug":
+		return DebugLevel, nil
+	default:
+		return OffLevel, fmt.Errorf("invalid log level: %s", name)
+	}
+}
+
 // Logger represents a logger instance
 type Logger struct {
 	level  LogLevel
@@ -58,23 +81,28 @@ var (
 	once sync.Once
 )
 
-// Init initializes the logger with the specified log level
-func Init(level LogLevel) error {
+// Init initializes the logger with the specified log level, writing to a
+// file in dir, or in DefaultDir if dir is empty
+func Init(level LogLevel, dir string) error {
 	var err error
 	once.Do(func() {
-		defaultLogger, err = NewLogger(level)
+		defaultLogger, err = NewLogger(level, dir)
 	})
 	return err
 }
 
-// NewLogger creates a new logger instance
-func NewLogger(level LogLevel) (*Logger, error) {
+// NewLogger creates a new logger instance writing to a file in dir, or in
+// DefaultDir if dir is empty
+func NewLogger(level LogLevel, dir string) (*Logger, error) {
 	if level == OffLevel {
 		return &Logger{level: level}, nil
 	}
 
 	// Create logs directory if it doesn't exist
-	logsDir := "logs"
+	logsDir := dir
+	if logsDir == "" {
+		logsDir = DefaultDir
+	}
 	if err := os.MkdirAll(logsDir, 0755); err != nil {
 		return nil, fmt.Errorf("failed to create logs directory: %w", err)
 	}
@@ -0,0 +1,33 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, data := range []string{"first", "second"} {
+		if err := WriteFile(dir, "file.json", []byte(data)); err != nil {
+			t.Fatalf("WriteFile(%q) error: %v", data, err)
+		}
+		got, err := os.ReadFile(filepath.Join(dir, "file.json"))
+		if err != nil || string(got) != data {
+			t.Errorf("file contents = %q, %v, want %q", got, err, data)
+		}
+	}
+	
+	// No temporary files are left behind
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want 1", len(entries))
+	}
+	
+	if err := WriteFile(filepath.Join(dir, "missing"), "file.json", nil); err == nil {
+		t.Error("WriteFile() to a missing directory succeeded")
+	}
+}
@@ -0,0 +1,46 @@
+package paths
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// AppName is the name of the application directory inside the user directories
+const AppName = "ygocdb-tui"
+
+// CacheDir returns the application cache directory, e.g. ~/.cache/ygocdb-tui
+func CacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate cache directory: %w", err)
+	}
+	return filepath.Join(dir, AppName), nil
+}
+
+// ConfigDir returns the application config directory, e.g. ~/.config/ygocdb-tui
+func ConfigDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate config directory: %w", err)
+	}
+	return filepath.Join(dir, AppName), nil
+}
+
+// DataDir returns the application data directory, e.g. ~/.local/share/ygocdb-tui.
+// It honours $XDG_DATA_HOME and falls back to the config directory on
+// platforms without a separate data directory.
+func DataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" && filepath.IsAbs(dir) {
+		return filepath.Join(dir, AppName), nil
+	}
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" || runtime.GOOS == "ios" || runtime.GOOS == "plan9" {
+		return ConfigDir()
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate data directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", AppName), nil
+}
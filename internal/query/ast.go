@@ -0,0 +1,268 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/cardinfo"
+)
+
+// Node is a node of the filter AST
+type Node interface {
+	// Match reports whether the card satisfies the node
+	Match(card *api.Card) bool
+	// String returns the node in query syntax
+	String() string
+}
+
+// And matches cards satisfying all of its nodes
+type And []Node
+
+// Match reports whether the card satisfies every node
+func (a And) Match(card *api.Card) bool {
+	for _, node := range a {
+		if !node.Match(card) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the node in query syntax
+func (a And) String() string {
+	return joinNodes(a, " ")
+}
+
+// Or matches cards satisfying any of its nodes
+type Or []Node
+
+// Match reports whether the card satisfies any node
+func (o Or) Match(card *api.Card) bool {
+	for _, node := range o {
+		if node.Match(card) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the node in query syntax
+func (o Or) String() string {
+	return "(" + joinNodes(o, " OR ") + ")"
+}
+
+// Not matches cards not satisfying its node
+type Not struct {
+	Node Node
+}
+
+// Match reports whether the card does not satisfy the node
+func (n Not) Match(card *api.Card) bool {
+	return !n.Node.Match(card)
+}
+
+// String returns the node in query syntax
+func (n Not) String() string {
+	return "-" + n.Node.String()
+}
+
+// Text matches cards whose names or effect text contain its value
+type Text struct {
+	Value  string
+	Phrase bool
+}
+
+// Match reports whether any name or the effect text contains the value
+func (t Text) Match(card *api.Card) bool {
+	needle := strings.ToLower(t.Value)
+	for _, s := range []string{
+		card.Text.Name, card.CnName, card.ScName, card.MdName, card.NwbbsN,
+		card.CnocgN, card.JpName, card.JpRuby, card.EnName, card.Text.PDesc, card.Text.Desc,
+	} {
+		if strings.Contains(strings.ToLower(s), needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the node in query syntax
+func (t Text) String() string {
+	if t.Phrase {
+		return fmt.Sprintf("%q", t.Value)
+	}
+	return t.Value
+}
+
+// TypeIs matches cards having all flags of a card type
+type TypeIs struct {
+	Flag cardinfo.Type
+}
+
+// Match reports whether the card has the type flags
+func (t TypeIs) Match(card *api.Card) bool {
+	return cardinfo.Type(card.Data.Type).Has(t.Flag)
+}
+
+// String returns the node in query syntax
+func (t TypeIs) String() string {
+	return "type:" + cardinfo.TypeName(t.Flag)
+}
+
+// RaceIs matches monsters of a race
+type RaceIs struct {
+	Race cardinfo.Race
+}
+
+// Match reports whether the card is a monster of the race
+func (r RaceIs) Match(card *api.Card) bool {
+	return cardinfo.Type(card.Data.Type).IsMonster() && cardinfo.Race(card.Data.Race) == r.Race
+}
+
+// String returns the node in query syntax
+func (r RaceIs) String() string {
+	return "race:" + r.Race.String()
+}
+
+// AttributeIs matches monsters of an attribute
+type AttributeIs struct {
+	Attribute cardinfo.Attribute
+}
+
+// Match reports whether the card is a monster of the attribute
+func (a AttributeIs) Match(card *api.Card) bool {
+	return cardinfo.Type(card.Data.Type).IsMonster() && cardinfo.Attribute(card.Data.Attrib) == a.Attribute
+}
+
+// String returns the node in query syntax
+func (a AttributeIs) String() string {
+	return "attr:" + a.Attribute.String()
+}
+
+// ArchetypeIs matches cards belonging to an archetype
+type ArchetypeIs struct {
+	Code int
+}
+
+// Match reports whether the card belongs to the archetype
+func (a ArchetypeIs) Match(card *api.Card) bool {
+	return cardinfo.IsArchetype(card.Data.Setcode, a.Code)
+}
+
+// String returns the node in query syntax
+func (a ArchetypeIs) String() string {
+	return fmt.Sprintf("set:%#x", a.Code)
+}
+
+// RegionIs matches cards by their scope
+type RegionIs struct {
+	Name string
+	Test func(cardinfo.OT) bool
+}
+
+// Match reports whether the card scope passes the test
+func (r RegionIs) Match(card *api.Card) bool {
+	return r.Test(cardinfo.OT(card.Data.OT))
+}
+
+// String returns the node in query syntax
+func (r RegionIs) String() string {
+	return "ot:" + r.Name
+}
+
+// Stat is a numeric field of a card
+type Stat int
+
+// Numeric fields
+const (
+	StatID Stat = iota
+	StatLevel
+	StatScale
+	StatAtk
+	StatDef
+	StatLink
+)
+
+// statNames are the query names of the numeric fields
+var statNames = map[Stat]string{
+	StatID:    "id",
+	StatLevel: "level",
+	StatScale: "scale",
+	StatAtk:   "atk",
+	StatDef:   "def",
+	StatLink:  "link",
+}
+
+// value returns the value of the field for the card, and whether the card
+// has the field at all
+func (s Stat) value(card *api.Card) (int, bool) {
+	typ := cardinfo.Type(card.Data.Type)
+	if s == StatID {
+		return card.ID, true
+	}
+	if !typ.IsMonster() {
+		return 0, false
+	}
+	stats := cardinfo.DecodeStats(typ, card.Data.Level, card.Data.Atk, card.Data.Def)
+	switch s {
+	case StatLevel:
+		return stats.Level, true
+	case StatScale:
+		return stats.LeftScale, typ.Has(cardinfo.TypePendulum)
+	case StatAtk:
+		return stats.Atk, true
+	case StatDef:
+		return stats.Def, !typ.Has(cardinfo.TypeLink)
+	case StatLink:
+		return stats.Level, typ.Has(cardinfo.TypeLink)
+	}
+	return 0, false
+}
+
+// Compare matches cards whose numeric field compares to a value
+type Compare struct {
+	Stat  Stat
+	Op    string
+	Value int
+}
+
+// Match reports whether the card has the field and it satisfies the comparison
+func (c Compare) Match(card *api.Card) bool {
+	value, ok := c.Stat.value(card)
+	if !ok {
+		return false
+	}
+	// A "?" stat only equals "?"
+	if (value == cardinfo.UnknownStat) != (c.Value == cardinfo.UnknownStat) {
+		return c.Op == "!="
+	}
+	switch c.Op {
+	case "=":
+		return value == c.Value
+	case "!=":
+		return value != c.Value
+	case ">":
+		return value > c.Value
+	case ">=":
+		return value >= c.Value
+	case "<":
+		return value < c.Value
+	case "<=":
+		return value <= c.Value
+	}
+	return false
+}
+
+// String returns the node in query syntax
+func (c Compare) String() string {
+	return statNames[c.Stat] + c.Op + cardinfo.FormatStat(c.Value)
+}
+
+// joinNodes joins the string forms of nodes with sep
+func joinNodes(nodes []Node, sep string) string {
+	parts := make([]string, len(nodes))
+	for i, node := range nodes {
+		parts[i] = node.String()
+	}
+	return strings.Join(parts, sep)
+}
@@ -0,0 +1,436 @@
+// Package query parses the structured search syntax, e.g.
+//
+//	attr:暗 type:synchro level>=8 atk>2500 "破坏"
+//
+// Terms are combined with AND; OR (or |) separates alternatives, a leading -
+// negates a term and parentheses group terms. Bare words and double-quoted
+// phrases match card names and effect text.
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"ygocdb-tui/internal/cardinfo"
+)
+
+// Query is a parsed search query
+type Query struct {
+	// Search is the free text to search the card source for. It is empty
+	// when the query only filters by card data.
+	Search string
+	// Filter is applied to the search results. It is nil for plain text
+	// queries.
+	Filter Node
+}
+
+// CardID returns the passcode the query consists of, if it is a bare
+// integer without any filter
+func (q *Query) CardID() (int, bool) {
+	if q.Filter != nil || q.Search == "" || strings.TrimLeft(q.Search, "0123456789") != "" {
+		return 0, false
+	}
+	id, err := strconv.Atoi(q.Search)
+	return id, err == nil && id > 0
+}
+
+// SyntaxError describes an invalid query
+type SyntaxError struct {
+	// Pos is the 1-based character position of the error
+	Pos int
+	Msg string
+}
+
+// Error returns the error message
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("查询语法错误 (第 %d 个字符): %s", e.Pos, e.Msg)
+}
+
+// tokenKind is the kind of a lexical token
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenPhrase
+	tokenLParen
+	tokenRParen
+	tokenOr
+	tokenNot
+	tokenEOF
+)
+
+// token is a lexical token of a query
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+// fieldPattern splits a field term such as "level>=8"
+var fieldPattern = regexp.MustCompile(`^(\pL+)(>=|<=|!=|:|>|<|=)(.*)$`)
+
+// Parse parses a query
+func Parse(input string) (*Query, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, &SyntaxError{Pos: tok.pos, Msg: "多余的右括号"}
+	}
+	if root == nil {
+		return &Query{}, nil
+	}
+
+	// Bare words at the top level are searched for rather than filtered by,
+	// phrases are searched for and then matched exactly
+	nodes, ok := root.(And)
+	if !ok {
+		nodes = And{root}
+	}
+	var terms []string
+	var filter And
+	for _, node := range nodes {
+		text, isText := node.(Text)
+		if isText {
+			terms = append(terms, text.Value)
+		}
+		if !isText || text.Phrase {
+			filter = append(filter, node)
+		}
+	}
+
+	q := &Query{Search: strings.Join(terms, " ")}
+	switch len(filter) {
+	case 0:
+	case 1:
+		q.Filter = filter[0]
+	default:
+		q.Filter = filter
+	}
+	return q, nil
+}
+
+// lex splits the input into tokens
+func lex(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, pos: pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, pos: pos})
+			i++
+		case r == '|':
+			tokens = append(tokens, token{kind: tokenOr, pos: pos})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && (i == 0 || isBoundary(runes[i-1])):
+			tokens = append(tokens, token{kind: tokenNot, pos: pos})
+			i++
+		case r == '"':
+			end := indexRune(runes, i+1, '"')
+			if end < 0 {
+				return nil, &SyntaxError{Pos: pos, Msg: "引号未闭合"}
+			}
+			tokens = append(tokens, token{kind: tokenPhrase, value: string(runes[i+1 : end]), pos: pos})
+			i = end + 1
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && !strings.ContainsRune(`()|"`, runes[i]) {
+				i++
+			}
+			// A field value may be quoted, e.g. set:"元素英雄"
+			value := string(runes[start:i])
+			if i < len(runes) && runes[i] == '"' && fieldPattern.MatchString(value) {
+				end := indexRune(runes, i+1, '"')
+				if end < 0 {
+					return nil, &SyntaxError{Pos: i + 1, Msg: "引号未闭合"}
+				}
+				value += string(runes[i+1 : end])
+				i = end + 1
+			}
+			kind := tokenWord
+			if value == "OR" {
+				kind = tokenOr
+			}
+			tokens = append(tokens, token{kind: kind, value: value, pos: pos})
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes) + 1}), nil
+}
+
+// isBoundary reports whether r may precede a negation
+func isBoundary(r rune) bool {
+	return unicode.IsSpace(r) || r == '(' || r == '|'
+}
+
+// indexRune returns the index of r in runes at or after start, or -1
+func indexRune(runes []rune, start int, r rune) int {
+	for i := start; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// parser is a recursive descent parser over tokens
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// peek returns the current token
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next consumes and returns the current token
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// parseOr parses: and (OR and)*
+func (p *parser) parseOr() (Node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	nodes := Or{}
+	if first != nil {
+		nodes = append(nodes, first)
+	}
+	for p.peek().kind == tokenOr {
+		tok := p.next()
+		node, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if first == nil || node == nil {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: "OR 两侧都需要条件"}
+		}
+		nodes = append(nodes, node)
+	}
+	switch len(nodes) {
+	case 0:
+		return nil, nil
+	case 1:
+		return nodes[0], nil
+	default:
+		return nodes, nil
+	}
+}
+
+// parseAnd parses: unary+
+func (p *parser) parseAnd() (Node, error) {
+	var nodes And
+	for {
+		switch p.peek().kind {
+		case tokenEOF, tokenRParen, tokenOr:
+			switch len(nodes) {
+			case 0:
+				return nil, nil
+			case 1:
+				return nodes[0], nil
+			default:
+				return nodes, nil
+			}
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+}
+
+// parseUnary parses: -unary | ( or ) | term
+func (p *parser) parseUnary() (Node, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenNot:
+		switch p.peek().kind {
+		case tokenEOF, tokenRParen, tokenOr:
+			return nil, &SyntaxError{Pos: tok.pos, Msg: "- 后缺少条件"}
+		}
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{Node: node}, nil
+	case tokenLParen:
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next().kind != tokenRParen {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: "括号未闭合"}
+		}
+		if node == nil {
+			return nil, &SyntaxError{Pos: tok.pos, Msg: "括号内缺少条件"}
+		}
+		return node, nil
+	case tokenPhrase:
+		return Text{Value: tok.value, Phrase: true}, nil
+	case tokenWord:
+		return parseTerm(tok)
+	default:
+		return nil, &SyntaxError{Pos: tok.pos, Msg: "意外的符号"}
+	}
+}
+
+// parseTerm parses a bare word or a field term
+func parseTerm(tok token) (Node, error) {
+	m := fieldPattern.FindStringSubmatch(tok.value)
+	if m == nil {
+		return Text{Value: tok.value}, nil
+	}
+	field, ok := fields[strings.ToLower(m[1])]
+	if !ok {
+		// Not a known field, e.g. part of a card name
+		return Text{Value: tok.value}, nil
+	}
+	op, value := m[2], m[3]
+	if value == "" {
+		return nil, &SyntaxError{Pos: tok.pos, Msg: fmt.Sprintf("%s 缺少值", m[1])}
+	}
+	node, err := field(op, value)
+	if err != nil {
+		return nil, &SyntaxError{Pos: tok.pos, Msg: err.Error()}
+	}
+	return node, nil
+}
+
+// fieldParser builds the node of a field term
+type fieldParser func(op, value string) (Node, error)
+
+// fields maps field names and their aliases to their parsers
+var fields = map[string]fieldParser{}
+
+func init() {
+	register := func(parse fieldParser, names ...string) {
+		for _, name := range names {
+			fields[name] = parse
+		}
+	}
+	register(parseType, "type", "类型")
+	register(parseRace, "race", "种族")
+	register(parseAttribute, "attr", "attribute", "属性")
+	register(parseArchetype, "set", "setcode", "archetype", "系列", "字段")
+	register(parseRegion, "ot", "region", "地区")
+	register(parseStat(StatID), "id", "密码")
+	register(parseStat(StatLevel), "level", "lv", "rank", "星级", "等级", "阶级")
+	register(parseStat(StatLink), "link", "连接")
+	register(parseStat(StatScale), "scale", "刻度")
+	register(parseStat(StatAtk), "atk", "攻击", "攻击力")
+	register(parseStat(StatDef), "def", "守备", "守备力")
+}
+
+// equality wraps node according to an equality operator
+func equality(op string, node Node) (Node, error) {
+	switch op {
+	case ":", "=":
+		return node, nil
+	case "!=":
+		return Not{Node: node}, nil
+	default:
+		return nil, fmt.Errorf("%s 只支持 : = != 比较", node.String())
+	}
+}
+
+// parseType parses a type term
+func parseType(op, value string) (Node, error) {
+	flag, ok := cardinfo.ParseType(value)
+	if !ok {
+		return nil, fmt.Errorf("未知类型: %s", value)
+	}
+	return equality(op, TypeIs{Flag: flag})
+}
+
+// parseRace parses a race term
+func parseRace(op, value string) (Node, error) {
+	race, ok := cardinfo.ParseRace(value)
+	if !ok {
+		return nil, fmt.Errorf("未知种族: %s", value)
+	}
+	return equality(op, RaceIs{Race: race})
+}
+
+// parseAttribute parses an attribute term
+func parseAttribute(op, value string) (Node, error) {
+	attr, ok := cardinfo.ParseAttribute(value)
+	if !ok {
+		return nil, fmt.Errorf("未知属性: %s", value)
+	}
+	return equality(op, AttributeIs{Attribute: attr})
+}
+
+// parseArchetype parses an archetype term given by name or code
+func parseArchetype(op, value string) (Node, error) {
+	code, ok := cardinfo.FindArchetype(value)
+	if !ok {
+		parsed, err := strconv.ParseInt(value, 0, 32)
+		if err != nil || parsed <= 0 || parsed > 0xffff {
+			return nil, fmt.Errorf("未知系列: %s", value)
+		}
+		code = int(parsed)
+	}
+	return equality(op, ArchetypeIs{Code: code})
+}
+
+// regionTests maps region names to their tests
+var regionTests = map[string]func(cardinfo.OT) bool{
+	"ocg":        cardinfo.OT.IsOCG,
+	"tcg":        cardinfo.OT.IsTCG,
+	"ocgonly":    func(ot cardinfo.OT) bool { return ot.IsOCG() && !ot.IsTCG() },
+	"tcgonly":    func(ot cardinfo.OT) bool { return ot.IsTCG() && !ot.IsOCG() },
+	"anime":      cardinfo.OT.IsUnofficial,
+	"custom":     cardinfo.OT.IsUnofficial,
+	"动画":         cardinfo.OT.IsUnofficial,
+	"unreleased": cardinfo.OT.IsUnreleased,
+	"未发售":        cardinfo.OT.IsUnreleased,
+}
+
+// parseRegion parses a region term
+func parseRegion(op, value string) (Node, error) {
+	name := strings.ToLower(value)
+	test, ok := regionTests[name]
+	if !ok {
+		return nil, fmt.Errorf("未知地区: %s (可用 ocg, tcg, ocgonly, tcgonly, anime, unreleased)", value)
+	}
+	return equality(op, RegionIs{Name: name, Test: test})
+}
+
+// parseStat returns the parser of a numeric field
+func parseStat(stat Stat) fieldParser {
+	return func(op, value string) (Node, error) {
+		if op == ":" {
+			op = "="
+		}
+		if value == "?" {
+			return Compare{Stat: stat, Op: op, Value: cardinfo.UnknownStat}, nil
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s 需要数字: %s", statNames[stat], value)
+		}
+		return Compare{Stat: stat, Op: op, Value: n}, nil
+	}
+}
@@ -0,0 +1,160 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/cardinfo"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		search string
+		filter string // Filter in query syntax, empty if nil
+	}{
+		{"empty", "  ", "", ""},
+		{"plain text", "龙", "龙", ""},
+		{"hyphen inside a word", "blue-eyes", "blue-eyes", ""},
+		{"unknown field is text", "foo:bar", "foo:bar", ""},
+		{"phrase is searched and filtered", `"破坏" 龙`, "破坏 龙", `"破坏"`},
+		{"fields", "attr:暗 type:synchro level>=8 atk>2500", "", "attr:暗 type:同调 level>=8 atk>2500"},
+		{"text with field", "龙 atk>2000", "龙", "atk>2000"},
+
+		// AND binds tighter than OR
+		{"OR of ANDs", "a b OR c", "", "(a b OR c)"},
+		{"pipe", "atk>1000 | def>1000 level=4", "", "(atk>1000 OR def>1000 level=4)"},
+		{"parentheses", "(atk>1000 OR def>1000) level=4", "", "(atk>1000 OR def>1000) level=4"},
+		{"lower-case or is text", "a or b", "a or b", ""},
+
+		{"negated field", "-type:spell 龙", "龙", "-type:魔法"},
+		{"negated text", "a -b", "a", "-b"},
+		{"negated group", "-(atk>1000 OR def>1000)", "", "-(atk>1000 OR def>1000)"},
+		{"double negation", "--atk>0", "", "--atk>0"},
+
+		{"Chinese aliases", "属性:暗 攻击力>=2000 种族:龙", "", "attr:暗 atk>=2000 race:龙"},
+		{"level aliases", "lv:4 rank<4 星级=3", "", "level=4 level<4 level=3"},
+		{"id alias", "密码:123", "", "id=123"},
+		{"archetype by name", `set:"元素英雄"`, "", "set:0x3008"},
+		{"region", "ot:tcgonly", "", "ot:tcgonly"},
+
+		{"unknown stat", "atk=?", "", "atk=?"},
+		{"not equal stat", "def!=0", "", "def!=0"},
+		{"not equal type", "type!=spell", "", "-type:魔法"},
+		{"number with filter", "12345 atk>2000", "12345", "atk>2000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.input, err)
+			}
+			filter := ""
+			if q.Filter != nil {
+				filter = q.Filter.String()
+			}
+			if q.Search != tt.search || filter != tt.filter {
+				t.Errorf("Parse(%q) = search %q, filter %q; want %q, %q", tt.input, q.Search, filter, tt.search, tt.filter)
+			}
+		})
+	}
+}
+
+func TestParseSyntaxError(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+		msg   string
+	}{
+		{`"abc`, 1, "引号未闭合"},
+		{`set:"元素英雄`, 5, "引号未闭合"},
+		{"atk>", 1, "atk 缺少值"},
+		{"a atk>x", 3, "atk 需要数字: x"},
+		{"type:foo", 1, "未知类型: foo"},
+		{"attr:水火", 1, "未知属性: 水火"},
+		{"type>spell", 1, "type:魔法 只支持 : = != 比较"},
+		{"a OR", 3, "OR 两侧都需要条件"},
+		{"OR a", 1, "OR 两侧都需要条件"},
+		{"a | | b", 3, "OR 两侧都需要条件"},
+		{"(a", 1, "括号未闭合"},
+		{"x (a", 3, "括号未闭合"},
+		{"a)", 2, "多余的右括号"},
+		{"()", 1, "括号内缺少条件"},
+		{"-)", 1, "- 后缺少条件"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.input)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Errorf("Parse(%q) error = %v, want a SyntaxError", tt.input, err)
+			continue
+		}
+		if syntaxErr.Pos != tt.pos || syntaxErr.Msg != tt.msg {
+			t.Errorf("Parse(%q) error at %d: %q, want at %d: %q", tt.input, syntaxErr.Pos, syntaxErr.Msg, tt.pos, tt.msg)
+		}
+	}
+}
+
+func TestQueryCardID(t *testing.T) {
+	tests := []struct {
+		input string
+		id    int
+		ok    bool
+	}{
+		{"14558127", 14558127, true},
+		{" 14558127 ", 14558127, true},
+		{"12345 atk>2000", 0, false},
+		{"39 type:xyz", 0, false},
+		{"39 希望皇", 0, false},
+		{`"12345"`, 0, false},
+		{"-1", 0, false},
+		{"0", 0, false},
+		{"龙", 0, false},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", tt.input, err)
+		}
+		if id, ok := q.CardID(); id != tt.id || ok != tt.ok {
+			t.Errorf("Parse(%q).CardID() = %d, %v; want %d, %v", tt.input, id, ok, tt.id, tt.ok)
+		}
+	}
+}
+
+func TestFilterMatch(t *testing.T) {
+	darkMagician := &api.Card{ID: 46986414, CnName: "黑魔术师", Data: api.Data{
+		Type: int(cardinfo.TypeMonster | cardinfo.TypeNormal), Atk: 2500, Def: 2100, Level: 7, Attrib: 0x20, Race: 0x2,
+	}}
+	potOfGreed := &api.Card{ID: 55144522, CnName: "强欲之壶", Data: api.Data{Type: int(cardinfo.TypeSpell)}}
+	unknownAtk := &api.Card{ID: 1, Data: api.Data{Type: int(cardinfo.TypeMonster), Atk: cardinfo.UnknownStat}}
+
+	tests := []struct {
+		input string
+		card  *api.Card
+		want  bool
+	}{
+		{"atk>=2500", darkMagician, true},
+		{"atk>2500", darkMagician, false},
+		{"atk>2500 OR def>2000", darkMagician, true},
+		{"level=7 atk>2500 OR def>3000", darkMagician, false},
+		{"-type:spell", darkMagician, true},
+		{"-type:spell", potOfGreed, false},
+		{"atk<3000", potOfGreed, false},
+		{"-(atk>3000 OR def>3000)", darkMagician, true},
+		{"atk=?", unknownAtk, true},
+		{"atk<1000", unknownAtk, false},
+		{"atk!=0", unknownAtk, true},
+		{`"魔术"`, darkMagician, true},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Fatalf("Parse(%q) error: %v", tt.input, err)
+		}
+		if got := q.Filter.Match(tt.card); got != tt.want {
+			t.Errorf("Parse(%q).Filter.Match(%d) = %v, want %v", tt.input, tt.card.ID, got, tt.want)
+		}
+	}
+}
@@ -1,40 +1,46 @@
 package ui
 
 import (
-	"strconv"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
 	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/cardinfo"
+	"ygocdb-tui/internal/deck"
 	"ygocdb-tui/internal/log"
+	"ygocdb-tui/internal/query"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// searchCardByIDCmd creates a command to search for a card by its passcode
+func searchCardByIDCmd(ctx context.Context, source api.CardSource, cardID int) tea.Cmd {
+	log.Info("Initiating search by card ID: %d", cardID)
+	
+	return func() tea.Msg {
+		log.Debug("Fetching card by ID: %d", cardID)
+		card, err := source.GetCardByIDContext(ctx, cardID)
+		if err != nil {
+			log.Error("Failed to fetch card by ID %d: %v", cardID, err)
+			return SearchErrorMsg{Err: err}
+		}
+		
+		log.Info("Successfully fetched card by ID: %d", card.ID)
+		return SearchByIDResultMsg{Card: card}
+	}
+}
+
 // searchCardsCmd creates a command to search for cards
-func searchCardsCmd(query string, start int) tea.Cmd {
+func searchCardsCmd(ctx context.Context, source api.CardSource, query string, start int) tea.Cmd {
 	log.Info("Initiating search command: query=%s, start=%d", query, start)
 	
 	return func() tea.Msg {
 		log.Debug("Search command executing in background")
 		
-		// Check if query is a number (card ID) and we're on the first page
-		if cardID, err := strconv.Atoi(query); err == nil && start == 0 {
-			log.Info("Query identified as card ID: %d", cardID)
-			
-			// Query by card ID (only for first page)
-			client := api.NewClient()
-			log.Debug("Fetching card by ID: %d", cardID)
-			card, err := client.GetCardByID(cardID)
-			if err != nil {
-				log.Error("Failed to fetch card by ID %d: %v", cardID, err)
-				return SearchErrorMsg{Err: err}
-			}
-			
-			log.Info("Successfully fetched card by ID: %d", card.ID)
-			return SearchByIDResultMsg{Card: card}
-		}
-		
 		// Search by name with pagination
 		log.Info("Performing name search: query=%s, start=%d", query, start)
-		client := api.NewClient()
-		results, err := client.SearchCards(query, start)
+		results, err := source.SearchCardsContext(ctx, query, start)
 		if err != nil {
 			log.Error("Failed to perform search: %v", err)
 			return SearchErrorMsg{Err: err}
@@ -46,15 +52,14 @@ func searchCardsCmd(query string, start int) tea.Cmd {
 }
 
 // getCardByIDCmd creates a command to get a card by ID
-func getCardByIDCmd(id int) tea.Cmd {
+func getCardByIDCmd(ctx context.Context, source api.CardSource, id int) tea.Cmd {
 	log.Info("Initiating get card by ID command: id=%d", id)
 	
 	return func() tea.Msg {
 		log.Debug("Get card command executing in background")
 		
-		client := api.NewClient()
 		log.Debug("Fetching card by ID: %d", id)
-		card, err := client.GetCardByID(id)
+		card, err := source.GetCardByIDContext(ctx, id)
 		if err != nil {
 			log.Error("Failed to fetch card by ID %d: %v", id, err)
 			return SearchErrorMsg{Err: err}
@@ -65,12 +70,142 @@ func getCardByIDCmd(id int) tea.Cmd {
 	}
 }
 
+// filterCardsCmd creates a command to list every card matching a filter
+func filterCardsCmd(ctx context.Context, source api.FilterSource, filter query.Node) tea.Cmd {
+	log.Info("Initiating filter command: filter=%s", filter)
+	
+	return func() tea.Msg {
+		cards, err := source.FilterCards(ctx, filter.Match)
+		if err != nil {
+			log.Error("Failed to filter cards: %v", err)
+			return SearchErrorMsg{Err: err}
+		}
+		
+		log.Info("Filter completed successfully, found %d results", len(cards))
+		return SearchResultMsg{Results: &api.SearchResponse{Result: cards}}
+	}
+}
+
+// loadDeckCmd creates a command to read a .ydk deck file and look up its cards
+func loadDeckCmd(ctx context.Context, source api.CardSource, path string) tea.Cmd {
+	log.Info("Initiating deck file command: path=%s", path)
+	
+	return func() tea.Msg {
+		passcodes, err := deck.ReadYDKFile(path)
+		if err != nil {
+			log.Error("Failed to read deck file %s: %v", path, err)
+			return SearchErrorMsg{Err: err}
+		}
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		return resolveDeck(ctx, source, name, passcodes)
+	}
+}
+
+// resolveDeckCmd creates a command to look up the cards of a deck
+func resolveDeckCmd(ctx context.Context, source api.CardSource, name string, passcodes *deck.Passcodes) tea.Cmd {
+	log.Info("Initiating deck command: name=%s", name)
+	
+	return func() tea.Msg {
+		return resolveDeck(ctx, source, name, passcodes)
+	}
+}
+
+// resolveDeck looks up the cards of a deck
+func resolveDeck(ctx context.Context, source api.CardSource, name string, passcodes *deck.Passcodes) tea.Msg {
+	d, err := deck.Resolve(ctx, source, name, passcodes)
+	var missing *deck.MissingError
+	if err != nil && !errors.As(err, &missing) {
+		log.Error("Failed to resolve deck %s: %v", name, err)
+		return SearchErrorMsg{Err: err}
+	}
+	
+	log.Info("Resolved deck %s with %d cards", name, d.Len())
+	return DeckResultMsg{Deck: d, Err: err}
+}
+
+// maxArchetypePages limits the search pages scanned to list an archetype
+// from a source that cannot list archetypes directly
+const maxArchetypePages = 10
+
+// archetypeCardsCmd creates a command to list the cards of an archetype
+func archetypeCardsCmd(ctx context.Context, source api.CardSource, archetype int) tea.Cmd {
+	log.Info("Initiating archetype command: archetype=%#x", archetype)
+	
+	return func() tea.Msg {
+		var (
+			cards []api.Card
+			err   error
+		)
+		if archetypes, ok := source.(api.ArchetypeSource); ok {
+			cards, err = archetypes.CardsByArchetype(ctx, archetype)
+		} else {
+			cards, err = searchArchetype(ctx, source, archetype)
+		}
+		if err != nil {
+			log.Error("Failed to list archetype %#x: %v", archetype, err)
+			return SearchErrorMsg{Err: err}
+		}
+		
+		log.Info("Found %d cards of archetype %#x", len(cards), archetype)
+		return SearchResultMsg{
+			Results: &api.SearchResponse{Result: cards},
+			Query:   cardinfo.ArchetypeName(archetype),
+		}
+	}
+}
+
+// searchArchetype lists the cards of an archetype by searching for its name
+// and keeping the cards that carry its setcode
+func searchArchetype(ctx context.Context, source api.CardSource, archetype int) ([]api.Card, error) {
+	name, ok := cardinfo.LookupArchetype(archetype)
+	if !ok {
+		return nil, fmt.Errorf("未知系列(%#x)，请使用本地数据或加载 strings.conf 后再浏览", archetype)
+	}
+	
+	cards := []api.Card{}
+	start := 0
+	for page := 0; page < maxArchetypePages; page++ {
+		results, err := source.SearchCardsContext(ctx, name, start)
+		if err != nil {
+			return nil, err
+		}
+		for _, card := range results.Result {
+			if cardinfo.IsArchetype(card.Data.Setcode, archetype) {
+				cards = append(cards, card)
+			}
+		}
+		if results.Next <= 0 {
+			break
+		}
+		start = results.Next
+	}
+	return cards, nil
+}
+
+// newRequestContext cancels any in-flight request and returns a context for
+// the next one
+func (m *Model) newRequestContext() context.Context {
+	m.cancelRequest()
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	return ctx
+}
+
+// cancelRequest cancels the in-flight request, if any
+func (m *Model) cancelRequest() {
+	if m.cancel != nil {
+		log.Debug("Cancelling in-flight request")
+		m.cancel()
+		m.cancel = nil
+	}
+}
+
 // nextPageCmd handles navigation to the next page
 func (m *Model) nextPageCmd() tea.Cmd {
 	log.Info("Checking if next page needs to be fetched")
 	
 	// Calculate the start index for the next page
-	nextPageStart := (m.currentPage + 1) * PageSize
+	nextPageStart := (m.currentPage + 1) * m.pageSize
 	
 	// If we have enough cached results, just update the page
 	if nextPageStart < len(m.results) {
@@ -85,7 +220,7 @@ func (m *Model) nextPageCmd() tea.Cmd {
 	if m.nextStart > 0 {
 		log.Info("Fetching next page from API, start=%d", m.nextStart)
 		m.loading = true
-		return searchCardsCmd(m.query, m.nextStart)
+		return searchCardsCmd(m.newRequestContext(), m.source, m.query, m.nextStart)
 	}
 	
 	log.Debug("No more pages available")
@@ -112,16 +247,16 @@ func (m *Model) autoFetchNextPageCmd() tea.Cmd {
 	currentPageResults := m.getCurrentPageResults()
 	
 	// Calculate if current page is the last page based on current results
-	expectedTotalPages := (len(m.results) + PageSize - 1) / PageSize
-	isLastPage := m.currentPage == expectedTotalPages-1
+	expectedTotalPages := (len(m.results) + m.pageSize - 1) / m.pageSize
+	isLastPage := m.currentPage >= expectedTotalPages-1
 	
-	// If current page has less than PageSize items and there are more results available
+	// If current page has less than a full page of items and there are more results available
 	// and we're on the last page, then auto-fetch more results
-	if len(currentPageResults) < PageSize && m.nextStart > 0 && isLastPage {
+	if len(currentPageResults) < m.pageSize && m.nextStart > 0 && isLastPage {
 		log.Info("Current page has %d items (less than %d), is the last page, and more results are available. Auto-fetching next page.", 
-			len(currentPageResults), PageSize)
+			len(currentPageResults), m.pageSize)
 		m.loading = true
-		return searchCardsCmd(m.query, m.nextStart)
+		return searchCardsCmd(m.newRequestContext(), m.source, m.query, m.nextStart)
 	}
 	
 	return nil
@@ -0,0 +1,319 @@
+package ui
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/deck"
+	"ygocdb-tui/internal/log"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// deckEntry is a distinct card of a deck zone
+type deckEntry struct {
+	zone deck.Zone
+	deck.Entry
+}
+
+// deckEntries returns the distinct cards of each zone of the deck in order
+func (m *Model) deckEntries() []deckEntry {
+	var entries []deckEntry
+	for _, zone := range deck.Zones {
+		for _, entry := range m.deck.Entries(zone) {
+			entries = append(entries, deckEntry{zone: zone, Entry: entry})
+		}
+	}
+	return entries
+}
+
+// selectedCard returns the card selected in ResultMode or shown in CardMode
+func (m *Model) selectedCard() (api.Card, bool) {
+	switch m.mode {
+	case ResultMode:
+		index := m.currentPage*m.pageSize + m.selected
+		if index >= 0 && index < len(m.results) {
+			return m.results[index], true
+		}
+	case CardMode:
+		if m.card != nil {
+			return m.card.Card, true
+		}
+	}
+	return api.Card{}, false
+}
+
+// addToDeck adds the selected card to the side deck if side is set, or to
+// the main or extra deck by its type otherwise
+func (m *Model) addToDeck(side bool) {
+	card, ok := m.selectedCard()
+	if !ok {
+		return
+	}
+	zone, err := m.deck.Add(card, side)
+	if err != nil {
+		log.Info("Cannot add card %d to the deck: %v", card.ID, err)
+		m.notice = fmt.Sprintf("无法加入卡组: %v", err)
+		return
+	}
+	log.Info("Added card %d to the %d zone", card.ID, zone)
+	m.deckModified = true
+	m.notice = fmt.Sprintf("已将 %s 加入%s (%d/%d)", card.DisplayName(m.nameLang), zone, len(m.deck.Cards(zone)), zone.Max())
+}
+
+// openDeckMode switches to the deck builder, returning to the current mode
+func (m *Model) openDeckMode() {
+	// A card opened from the deck returns to where the deck was opened from
+	if m.mode != DeckMode && m.mode != DeckListMode && !(m.mode == CardMode && m.cardReturn == DeckMode) {
+		m.deckReturn = m.mode
+	}
+	log.Info("Opening deck builder")
+	m.textInput.Blur()
+	m.mode = DeckMode
+	m.deckSelected = min(m.deckSelected, max(len(m.deckEntries())-1, 0))
+}
+
+// closeDeckMode returns from the deck builder to the mode it was opened from
+func (m *Model) closeDeckMode() {
+	log.Info("Closing deck builder")
+	m.mode = m.deckReturn
+	if m.mode == SearchMode {
+		m.textInput.Focus()
+	}
+}
+
+// handleDeckRunes handles a key that types characters in DeckMode and
+// DeckListMode
+func (m *Model) handleDeckRunes(key string) (tea.Cmd, bool) {
+	if m.mode == DeckListMode {
+		return nil, true
+	}
+	
+	switch key {
+	case "x":
+		// Remove a copy of the selected card
+		entries := m.deckEntries()
+		if m.deckSelected >= len(entries) {
+			return nil, true
+		}
+		entry := entries[m.deckSelected]
+		cards := m.deck.Cards(entry.zone)
+		for i := len(cards) - 1; i >= 0; i-- {
+			if cards[i].ID == entry.Card.ID {
+				m.deck.Remove(entry.zone, i)
+				break
+			}
+		}
+		log.Info("Removed card %d from the deck", entry.Card.ID)
+		m.deckModified = true
+		m.deckSelected = min(m.deckSelected, max(len(m.deckEntries())-1, 0))
+		return nil, true
+		
+	case "w":
+		// Save the deck under a name
+		if m.deckDir == "" {
+			m.notice = "无法保存卡组: 未找到数据目录"
+			return nil, true
+		}
+		return m.startPrompt(promptDeckName, m.deck.Name), true
+		
+	case "o":
+		// Choose a saved deck to open
+		if !m.confirmDiscard(key, key) {
+			return nil, true
+		}
+		decks, err := deck.List(m.deckDir)
+		if err != nil {
+			log.Error("Failed to list decks: %v", err)
+			m.notice = fmt.Sprintf("无法读取卡组列表: %v", err)
+			return nil, true
+		}
+		if len(decks) == 0 {
+			m.notice = "没有已保存的卡组"
+			return nil, true
+		}
+		m.decks = decks
+		m.deckListSelected = 0
+		m.err = nil
+		m.mode = DeckListMode
+		return nil, true
+		
+	case "e":
+		// Export the deck as a .ydk deck file
+		if m.deckDir == "" || m.deck.Name == "" {
+			m.notice = "请先按 w 保存卡组"
+			return nil, true
+		}
+		path, err := deck.ExportYDK(m.deckDir, m.deck)
+		if err != nil {
+			log.Error("Failed to export deck %s: %v", m.deck.Name, err)
+			m.notice = fmt.Sprintf("导出失败: %v", err)
+			return nil, true
+		}
+		log.Info("Exported deck %s to %s", m.deck.Name, path)
+		m.notice = fmt.Sprintf("已导出到 %s", path)
+		return nil, true
+		
+	case "v":
+		// Toggle the deck legality panel
+		m.validating = !m.validating
+		return nil, true
+		
+	case "r":
+		// Cycle the region the deck is checked against
+		m.region = m.region.Next()
+		log.Info("Region changed to %s", m.region)
+		m.refilterResults()
+		return nil, true
+		
+	case "y":
+		// Show the deck as a ydke:// link to share
+		m.notice = m.deck.Passcodes().YDKE()
+		log.Info("Exported deck as ydke link")
+		return nil, true
+		
+	case "n":
+		// Start a new deck
+		if !m.confirmDiscard(key, key) {
+			return nil, true
+		}
+		log.Info("Starting a new deck")
+		m.deck = deck.New("")
+		m.deckModified = false
+		m.deckSelected = 0
+		return nil, true
+	}
+	
+	return nil, false
+}
+
+// saveDeck saves the deck under a name and reports whether it was saved
+func (m *Model) saveDeck(name string) bool {
+	name = strings.TrimSpace(name)
+	if !deck.ValidName(name) {
+		m.notice = deck.ErrInvalidName.Error()
+		return false
+	}
+	m.deck.Name = name
+	if err := deck.Save(m.deckDir, m.deck); err != nil {
+		log.Error("Failed to save deck %s: %v", name, err)
+		m.notice = fmt.Sprintf("保存失败: %v", err)
+		return false
+	}
+	log.Info("Saved deck %s", name)
+	m.deckModified = false
+	m.notice = fmt.Sprintf("已保存卡组 %s", name)
+	return true
+}
+
+// openSavedDeck replaces the deck with the deck selected in DeckListMode.
+// The cards of .ydk deck files are looked up in the card source.
+func (m *Model) openSavedDeck() tea.Cmd {
+	name := m.decks[m.deckListSelected]
+	if strings.EqualFold(filepath.Ext(name), deck.YDKExt) {
+		m.err = nil
+		m.loading = true
+		return loadDeckCmd(m.newRequestContext(), m.source, filepath.Join(m.deckDir, name))
+	}
+	
+	d, err := deck.Load(m.deckDir, name)
+	if err != nil {
+		log.Error("Failed to open deck %s: %v", name, err)
+		m.notice = fmt.Sprintf("无法打开卡组: %v", err)
+		return nil
+	}
+	log.Info("Opened deck %s with %d cards", name, d.Len())
+	m.setDeck(d)
+	return nil
+}
+
+// openYDKECmd opens the deck of a ydke:// link
+func (m *Model) openYDKECmd(link string) tea.Cmd {
+	passcodes, err := deck.ParseYDKE(link)
+	if err != nil {
+		log.Warn("Invalid ydke link: %v", err)
+		m.err = err
+		return nil
+	}
+	if !m.confirmDiscard("enter", "Enter") {
+		return nil
+	}
+	log.Info("Opening deck of ydke link")
+	m.err = nil
+	m.loading = true
+	m.textInput.Blur()
+	return resolveDeckCmd(m.newRequestContext(), m.source, "", passcodes)
+}
+
+// confirmDiscard reports whether the deck may be replaced or dropped by the
+// action of key. If the deck has unsaved changes, the user is warned and must
+// press the key, shown as label, again right away to discard them.
+func (m *Model) confirmDiscard(key, label string) bool {
+	if !m.deckModified || m.discardKey == key {
+		m.discardKey = ""
+		return true
+	}
+	log.Info("Asking to confirm discarding the unsaved deck")
+	m.discardKey = key
+	save := "按 w 保存"
+	if m.mode != DeckMode {
+		save = "按 Ctrl+D 打开卡组后按 w 保存"
+	}
+	m.notice = fmt.Sprintf("当前卡组有未保存的修改，再按一次 %s 放弃修改，或%s", label, save)
+	return false
+}
+
+// setDeck replaces the deck and shows it
+func (m *Model) setDeck(d *deck.Deck) {
+	if m.mode != DeckMode && m.mode != DeckListMode {
+		m.deckReturn = m.mode
+	}
+	m.deck = d
+	m.deckModified = false
+	m.deckSelected = 0
+	m.mode = DeckMode
+}
+
+// formatViolations formats the rules the deck violates under the active
+// banlist and region
+func (m *Model) formatViolations() string {
+	violations := deck.Validate(m.deck, deck.Rules{
+		Banlist:  m.banlist,
+		Region:   m.region,
+		NameLang: m.nameLang,
+	})
+	
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf("合法性检查 (禁限卡表: %s，地区: %s)\n", m.banlistLabel(), m.region))
+	if len(violations) == 0 {
+		b.WriteString("卡组合法")
+		return b.String()
+	}
+	for i, violation := range violations {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("- " + violation.Message)
+	}
+	return b.String()
+}
+
+// formatDeck formats the zones of the deck with the selected entry marked
+func (m *Model) formatDeck() string {
+	var b strings.Builder
+	entries := m.deckEntries()
+	i := 0
+	for _, zone := range deck.Zones {
+		b.WriteString(fmt.Sprintf("%s (%d/%d)\n", zone, len(m.deck.Cards(zone)), zone.Max()))
+		for ; i < len(entries) && entries[i].zone == zone; i++ {
+			line := fmt.Sprintf("%d× %s", entries[i].Count, FormatCardSummary(entries[i].Card, m.nameLang, m.banlist))
+			if i == m.deckSelected {
+				b.WriteString("> " + selectedStyle.Render(line) + "\n")
+			} else {
+				b.WriteString("  " + line + "\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/deck"
+	"ygocdb-tui/internal/format"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// newTestModel creates a model with an unsaved deck of one card
+func newTestModel() Model {
+	m := NewModel(nil, Options{ExportFormat: format.Formats()[0]})
+	m.deck.Main = append(m.deck.Main, api.Card{ID: 1})
+	m.deckModified = true
+	return m
+}
+
+// press sends a key to the model
+func press(m Model, key tea.KeyMsg) (Model, tea.Cmd) {
+	updated, cmd := m.Update(key)
+	return updated.(Model), cmd
+}
+
+// isQuit reports whether cmd quits the program
+func isQuit(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	_, ok := cmd().(tea.QuitMsg)
+	return ok
+}
+
+func TestQuitConfirmsUnsavedDeck(t *testing.T) {
+	for _, key := range []tea.KeyMsg{{Type: tea.KeyEsc}, {Type: tea.KeyCtrlC}} {
+		m, cmd := press(newTestModel(), key)
+		if isQuit(cmd) {
+			t.Fatalf("%s quit with an unsaved deck", key)
+		}
+		if !strings.Contains(m.notice, "未保存") {
+			t.Errorf("%s notice = %q, want a warning", key, m.notice)
+		}
+		if _, cmd = press(m, key); !isQuit(cmd) {
+			t.Errorf("%s pressed twice did not quit", key)
+		}
+	}
+
+	// Another key cancels the confirmation
+	m, _ := press(newTestModel(), tea.KeyMsg{Type: tea.KeyEsc})
+	m, _ = press(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	if _, cmd := press(m, tea.KeyMsg{Type: tea.KeyEsc}); isQuit(cmd) {
+		t.Error("Esc quit after the confirmation was cancelled")
+	}
+
+	// A saved deck quits right away
+	m = newTestModel()
+	m.deckModified = false
+	if _, cmd := press(m, tea.KeyMsg{Type: tea.KeyEsc}); !isQuit(cmd) {
+		t.Error("Esc did not quit with a saved deck")
+	}
+}
+
+func TestNewDeckConfirmsUnsavedDeck(t *testing.T) {
+	m := newTestModel()
+	m.openDeckMode()
+	n := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")}
+	if m, _ = press(m, n); m.deck.Len() != 1 {
+		t.Fatal("n replaced an unsaved deck")
+	}
+	if m, _ = press(m, n); m.deck.Len() != 0 || m.deckModified {
+		t.Errorf("n pressed twice kept the deck: %d cards, modified %v", m.deck.Len(), m.deckModified)
+	}
+}
+
+func TestOpenYDKEConfirmsUnsavedDeck(t *testing.T) {
+	m := newTestModel()
+	link := (&deck.Passcodes{Main: []int{2}}).YDKE()
+	m.textInput.SetValue(link)
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	m, cmd := press(m, enter)
+	if cmd != nil || m.loading {
+		t.Fatal("a ydke:// link replaced an unsaved deck")
+	}
+	if m, cmd = press(m, enter); cmd == nil || !m.loading {
+		t.Error("Enter pressed twice did not open the ydke:// link")
+	}
+	m.cancelRequest()
+}
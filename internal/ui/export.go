@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+	"ygocdb-tui/internal/format"
+	"ygocdb-tui/internal/log"
+)
+
+// exportResults writes the fetched search results to a file in the export
+// format in the export directory
+func (m *Model) exportResults() {
+	if m.exportDir == "" {
+		m.notice = "无法导出: 未找到数据目录"
+		return
+	}
+	if len(m.results) == 0 {
+		m.notice = "没有可导出的搜索结果"
+		return
+	}
+	path, err := writeExport(m.exportDir, "results", m.exportFormat.Ext, func(f *os.File) error {
+		return m.exportFormat.Write(f, m.results, m.nameLang)
+	})
+	if err != nil {
+		log.Error("Failed to export results: %v", err)
+		m.notice = fmt.Sprintf("导出失败: %v", err)
+		return
+	}
+	log.Info("Exported %d results as %s to %s", len(m.results), m.exportFormat.Name, path)
+	m.notice = fmt.Sprintf("已导出 %d 张卡片到 %s", len(m.results), path)
+	if m.nextStart > 0 {
+		m.notice += "（仅包含已加载的结果，翻到最后一页可加载更多）"
+	}
+}
+
+// cycleExportFormat switches to the next registered export format
+func (m *Model) cycleExportFormat() {
+	m.exportFormat = format.Next(m.exportFormat)
+	log.Info("Export format changed to %s", m.exportFormat.Name)
+	m.notice = "导出格式: " + m.exportFormat.Name
+}
+
+// writeExport creates a timestamped file in dir and writes it with write.
+// A number is appended to the name if a file with that name already exists.
+func writeExport(dir, prefix, ext string, write func(f *os.File) error) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	name := prefix + "-" + time.Now().Format("20060102-150405")
+	path := filepath.Join(dir, name+ext)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	for n := 2; os.IsExist(err); n++ {
+		path = filepath.Join(dir, fmt.Sprintf("%s-%d%s", name, n, ext))
+		f, err = os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	}
+	if err != nil {
+		return "", err
+	}
+	err = write(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
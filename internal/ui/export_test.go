@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteExportKeepsExistingFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "exports")
+	var paths []string
+	for i := 0; i < 3; i++ {
+		path, err := writeExport(dir, "deck", ".ydk", func(f *os.File) error {
+			_, err := f.WriteString(filepath.Base(f.Name()))
+			return err
+		})
+		if err != nil {
+			t.Fatalf("writeExport() error: %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	seen := map[string]bool{}
+	for _, path := range paths {
+		if seen[path] {
+			t.Fatalf("writeExport() returned %s twice", path)
+		}
+		seen[path] = true
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != filepath.Base(path) {
+			t.Errorf("%s was overwritten with %q", path, data)
+		}
+	}
+}
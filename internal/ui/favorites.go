@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"ygocdb-tui/internal/favorites"
+	"ygocdb-tui/internal/log"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// toggleFavorite stars or unstars the selected card
+func (m *Model) toggleFavorite() {
+	card, ok := m.selectedCard()
+	if !ok {
+		return
+	}
+	if m.favorites == nil {
+		m.notice = "无法收藏: 未找到数据目录"
+		return
+	}
+	starred, err := m.favorites.Toggle(card)
+	if err != nil {
+		log.Error("Failed to save favorites: %v", err)
+		m.notice = fmt.Sprintf("保存收藏失败: %v", err)
+		return
+	}
+	log.Info("Card %d starred: %t", card.ID, starred)
+	if starred {
+		m.notice = fmt.Sprintf("已收藏 %s", card.DisplayName(m.nameLang))
+	} else {
+		m.notice = fmt.Sprintf("已取消收藏 %s", card.DisplayName(m.nameLang))
+	}
+}
+
+// isFavorite reports whether a card is starred
+func (m *Model) isFavorite(id int) bool {
+	return m.favorites != nil && m.favorites.Has(id)
+}
+
+// openFavoritesMode switches to the favorites screen, returning to the
+// current mode
+func (m *Model) openFavoritesMode() {
+	if m.favorites == nil {
+		m.notice = "无法使用收藏: 未找到数据目录"
+		return
+	}
+	// A card opened from the favorites returns to where they were opened from
+	if !(m.mode == CardMode && m.cardReturn == FavoritesMode) {
+		m.favoritesReturn = m.mode
+	}
+	log.Info("Opening favorites")
+	m.textInput.Blur()
+	m.mode = FavoritesMode
+	m.refreshFavorites()
+}
+
+// closeFavoritesMode returns from the favorites screen to the mode it was
+// opened from
+func (m *Model) closeFavoritesMode() {
+	log.Info("Closing favorites")
+	m.mode = m.favoritesReturn
+	if m.mode == SearchMode {
+		m.textInput.Focus()
+	}
+}
+
+// refreshFavorites lists the favorites by the current tag and order,
+// keeping the selection in range
+func (m *Model) refreshFavorites() {
+	m.favoritesList = m.favorites.List(m.favoritesTag, m.favoritesOrder, m.nameLang)
+	m.favoritesSelected = min(m.favoritesSelected, max(len(m.favoritesList)-1, 0))
+}
+
+// handleFavoritesRunes handles a key that types characters in FavoritesMode
+func (m *Model) handleFavoritesRunes(key string) (tea.Cmd, bool) {
+	switch key {
+	case "x":
+		// Unstar the selected card
+		if len(m.favoritesList) == 0 {
+			return nil, true
+		}
+		card := m.favoritesList[m.favoritesSelected].Card
+		if _, err := m.favorites.Toggle(card); err != nil {
+			log.Error("Failed to save favorites: %v", err)
+			m.notice = fmt.Sprintf("保存收藏失败: %v", err)
+		}
+		m.refreshFavorites()
+		return nil, true
+		
+	case "t":
+		// Edit the tags of the selected card
+		if len(m.favoritesList) == 0 {
+			return nil, true
+		}
+		tags := m.favoritesList[m.favoritesSelected].Tags
+		return m.startPrompt(promptTags, strings.Join(tags, " ")), true
+		
+	case "f":
+		// List the favorites by tag
+		return m.startPrompt(promptTagFilter, m.favoritesTag), true
+		
+	case "o":
+		// Cycle the order
+		m.favoritesOrder = m.favoritesOrder.Next()
+		log.Info("Favorites order changed to %s", m.favoritesOrder)
+		m.refreshFavorites()
+		return nil, true
+		
+	case "e":
+		// Export the listed favorites
+		m.exportFavorites()
+		return nil, true
+		
+	case "l":
+		// Cycle the name language
+		m.nameLang = m.nameLang.Next()
+		m.refreshFavorites()
+		return nil, true
+	}
+	
+	return nil, true
+}
+
+// setFavoriteTags replaces the tags of the selected favorite
+func (m *Model) setFavoriteTags(input string) {
+	if len(m.favoritesList) == 0 {
+		return
+	}
+	id := m.favoritesList[m.favoritesSelected].Card.ID
+	if err := m.favorites.SetTags(id, favorites.ParseTags(input)); err != nil {
+		log.Error("Failed to save favorites: %v", err)
+		m.notice = fmt.Sprintf("保存收藏失败: %v", err)
+	}
+	m.refreshFavorites()
+}
+
+// filterFavorites lists the favorites tagged with the input, or all
+// favorites if it is empty
+func (m *Model) filterFavorites(input string) {
+	m.favoritesTag = strings.TrimSpace(input)
+	m.favoritesSelected = 0
+	m.refreshFavorites()
+}
+
+// exportFavorites writes the listed favorites to a JSON file in the export
+// directory
+func (m *Model) exportFavorites() {
+	if m.exportDir == "" {
+		m.notice = "无法导出: 未找到数据目录"
+		return
+	}
+	path, err := writeExport(m.exportDir, "favorites", ".json", func(f *os.File) error {
+		return favorites.Export(f, m.favoritesList)
+	})
+	if err != nil {
+		log.Error("Failed to export favorites: %v", err)
+		m.notice = fmt.Sprintf("导出失败: %v", err)
+		return
+	}
+	log.Info("Exported %d favorites to %s", len(m.favoritesList), path)
+	m.notice = fmt.Sprintf("已导出 %d 张收藏到 %s", len(m.favoritesList), path)
+}
+
+// formatFavorites formats the listed favorites with the selected one marked
+func (m *Model) formatFavorites() string {
+	if len(m.favoritesList) == 0 {
+		if m.favoritesTag != "" {
+			return fmt.Sprintf("没有标签为 %s 的收藏\n\n", m.favoritesTag)
+		}
+		return "还没有收藏的卡片，在搜索结果或卡片详情中按 f 收藏\n\n"
+	}
+	
+	var b strings.Builder
+	for i, f := range m.favoritesList {
+		line := FormatCardSummary(f.Card, m.nameLang, m.banlist)
+		for _, tag := range f.Tags {
+			line += " #" + tag
+		}
+		if i == m.favoritesSelected {
+			b.WriteString("> " + selectedStyle.Render(line) + "\n")
+		} else {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
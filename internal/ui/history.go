@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/history"
+	"ygocdb-tui/internal/log"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// recordSearch adds a query to the search history; its result count is
+// recorded when the results arrive
+func (m *Model) recordSearch(input string) {
+	m.historyIndex = 0
+	if m.history == nil {
+		return
+	}
+	if err := m.history.Add(input); err != nil {
+		log.Warn("Failed to save search history: %v", err)
+	}
+	m.historyQuery = input
+}
+
+// recordCount records the result count of the last recorded query
+func (m *Model) recordCount(count int, more bool) {
+	if m.history == nil || m.historyQuery == "" {
+		return
+	}
+	if err := m.history.SetCount(m.historyQuery, count, more); err != nil {
+		log.Warn("Failed to save search history: %v", err)
+	}
+	m.historyQuery = ""
+}
+
+// recordError records the result count of the last recorded query from a
+// failed search: no results if the card was not found
+func (m *Model) recordError(err error) {
+	if errors.Is(err, api.ErrNotFound) {
+		m.recordCount(0, false)
+	}
+	m.historyQuery = ""
+}
+
+// recallHistory replaces the search input with an older (step 1) or newer
+// (step -1) query from the history, restoring the typed input past the
+// newest one
+func (m *Model) recallHistory(step int) {
+	if m.history == nil || m.history.Len() == 0 {
+		return
+	}
+	index := m.historyIndex + step
+	if index > m.history.Len() {
+		return
+	}
+	if m.historyIndex == 0 {
+		m.historyDraft = m.textInput.Value()
+	}
+	m.historyIndex = max(index, 0)
+	if m.historyIndex == 0 {
+		m.textInput.SetValue(m.historyDraft)
+	} else {
+		m.textInput.SetValue(m.history.Entries()[m.historyIndex-1].Query)
+	}
+	m.textInput.CursorEnd()
+}
+
+// openHistoryMode switches to the search history screen
+func (m *Model) openHistoryMode() tea.Cmd {
+	if m.history == nil {
+		m.err = fmt.Errorf("无法使用搜索历史: 未找到数据目录")
+		return nil
+	}
+	log.Info("Opening search history")
+	m.mode = HistoryMode
+	m.textInput.Blur()
+	m.promptInput.Placeholder = "模糊搜索历史"
+	m.promptInput.SetValue("")
+	m.historySelected = 0
+	m.refreshHistory()
+	return m.promptInput.Focus()
+}
+
+// closeHistoryMode returns to SearchMode
+func (m *Model) closeHistoryMode() {
+	log.Info("Closing search history")
+	m.mode = SearchMode
+	m.promptInput.Blur()
+	m.textInput.Focus()
+}
+
+// refreshHistory lists the history entries matching the filter input,
+// keeping the selection in range
+func (m *Model) refreshHistory() {
+	m.historyList = history.Filter(m.history.Entries(), m.promptInput.Value())
+	m.historySelected = min(m.historySelected, max(len(m.historyList)-1, 0))
+}
+
+// updateHistory handles a key in HistoryMode
+func (m Model) updateHistory(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC, tea.KeyCtrlR:
+		m.closeHistoryMode()
+		return m, nil
+		
+	case tea.KeyUp:
+		if n := len(m.historyList); n > 0 {
+			m.historySelected = (m.historySelected + n - 1) % n
+		}
+		return m, nil
+		
+	case tea.KeyDown:
+		if n := len(m.historyList); n > 0 {
+			m.historySelected = (m.historySelected + 1) % n
+		}
+		return m, nil
+		
+	case tea.KeyDelete:
+		// Forget the selected query
+		if len(m.historyList) > 0 {
+			query := m.historyList[m.historySelected].Query
+			if err := m.history.Remove(query); err != nil {
+				log.Warn("Failed to save search history: %v", err)
+			}
+			m.refreshHistory()
+		}
+		return m, nil
+		
+	case tea.KeyEnter:
+		// Search for the selected query again
+		if len(m.historyList) == 0 {
+			return m, nil
+		}
+		query := m.historyList[m.historySelected].Query
+		m.closeHistoryMode()
+		m.textInput.SetValue(query)
+		m.textInput.CursorEnd()
+		return m, m.searchCmd(query)
+	}
+	
+	var cmd tea.Cmd
+	m.promptInput, cmd = m.promptInput.Update(msg)
+	m.refreshHistory()
+	return m, cmd
+}
+
+// formatHistory formats the listed history entries with the selected one marked
+func (m *Model) formatHistory() string {
+	if len(m.historyList) == 0 {
+		return "没有匹配的搜索记录\n\n"
+	}
+	
+	var b strings.Builder
+	start := max(m.historySelected-m.pageSize+1, 0)
+	end := min(start+m.pageSize, len(m.historyList))
+	for i := start; i < end; i++ {
+		entry := m.historyList[i]
+		stats := helpStyle(formatHistoryStats(entry))
+		if i == m.historySelected {
+			b.WriteString("> " + selectedStyle.Render(entry.Query) + "  " + stats + "\n")
+		} else {
+			b.WriteString("  " + entry.Query + "  " + stats + "\n")
+		}
+	}
+	b.WriteString("\n")
+	b.WriteString(paginationStyle.Render(fmt.Sprintf("%d / %d", m.historySelected+1, len(m.historyList))))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// formatHistoryStats formats the result count, uses and last use of a query
+func formatHistoryStats(entry history.Entry) string {
+	count := "结果数未知"
+	switch {
+	case entry.Count < 0:
+	case entry.More:
+		count = fmt.Sprintf("%d+ 张", entry.Count)
+	default:
+		count = fmt.Sprintf("%d 张", entry.Count)
+	}
+	return fmt.Sprintf("%s，%d 次，%s", count, entry.Uses, entry.LastUsed.Format("2006-01-02 15:04"))
+}
@@ -1,6 +1,9 @@
 package ui
 
-import "ygocdb-tui/internal/api"
+import (
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/deck"
+)
 
 // SearchResultMsg represents a message containing search results
 type SearchResultMsg struct {
@@ -19,6 +22,13 @@ type CardResultMsg struct {
 	Card *api.GetCardResponse
 }
 
+// DeckResultMsg represents a message containing a deck whose cards were
+// looked up. Err reports cards that could not be found.
+type DeckResultMsg struct {
+	Deck *deck.Deck
+	Err  error
+}
+
 // SearchErrorMsg represents a message containing a search error
 type SearchErrorMsg struct {
 	Err error
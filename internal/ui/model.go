@@ -1,15 +1,28 @@
 package ui
 
 import (
+	"context"
 	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/banlist"
+	"ygocdb-tui/internal/cardinfo"
+	"ygocdb-tui/internal/deck"
+	"ygocdb-tui/internal/favorites"
+	"ygocdb-tui/internal/format"
+	"ygocdb-tui/internal/history"
 	"ygocdb-tui/internal/log"
+	"ygocdb-tui/internal/query"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/bubbles/textinput"
 )
 
 const (
-	// PageSize is the number of items to display per page
-	PageSize = 10
+	// DefaultPageSize is the number of items to display per page by default
+	DefaultPageSize = 10
+	// DefaultInputCharLimit is the default maximum length of the search
+	// input, long enough for ydke:// links of full decks
+	DefaultInputCharLimit = 1024
+	// DefaultInputWidth is the default width of the search input
+	DefaultInputWidth = 40
 )
 
 // Mode represents the current UI mode
@@ -22,46 +35,150 @@ const (
 	ResultMode
 	// CardMode is the mode for displaying card details
 	CardMode
+	// ArchetypeMode is the mode for choosing an archetype of the card to browse
+	ArchetypeMode
+	// DeckMode is the mode for building a deck
+	DeckMode
+	// DeckListMode is the mode for choosing a saved deck to open
+	DeckListMode
+	// FavoritesMode is the mode for listing starred cards
+	FavoritesMode
+	// HistoryMode is the mode for choosing a past query to search again
+	HistoryMode
 )
 
 // Model represents the application state
 type Model struct {
-	textInput   textinput.Model
-	results     []api.Card // All cached results
-	currentPage int        // Current page index (0-based)
-	totalPages  int        // Total number of pages
-	card        *api.GetCardResponse
-	selected    int
-	err         error
-	mode        Mode
-	loading     bool
-	apiClient   *api.Client
-	query       string
-	nextStart   int   // Next start position for API request
+	textInput         textinput.Model
+	rawResults        []api.Card // All fetched results before filtering
+	results           []api.Card // All cached results
+	currentPage       int        // Current page index (0-based)
+	totalPages        int        // Total number of pages
+	pageSize          int        // Number of items to display per page
+	card              *api.GetCardResponse
+	selected          int
+	err               error
+	mode              Mode
+	loading           bool
+	source            api.CardSource
+	query             string
+	nextStart         int                  // Next start position for API request
+	cancel            context.CancelFunc   // Cancels the in-flight request
+	archetypes        []int                // Archetype codes of the displayed card
+	archetypeSelected int                  // Selected archetype index in ArchetypeMode
+	region            cardinfo.Region      // Region the results are limited to
+	filter            query.Node           // Filter of the current query, if any
+	nameLang          api.NameLang         // Preferred language of card names
+	deck              *deck.Deck           // Deck being built
+	deckDir           string               // Directory decks are saved in
+	deckModified      bool                 // Whether the deck changed since it was saved
+	discardKey        string               // Key to press again to discard the unsaved deck
+	deckSelected      int                  // Selected entry index in DeckMode
+	deckReturn        Mode                 // Mode DeckMode returns to
+	decks             []string             // Names of the saved decks in DeckListMode
+	deckListSelected  int                  // Selected deck index in DeckListMode
+	promptInput       textinput.Model      // Input for names and tags
+	prompt            promptKind           // What the prompt input asks for, if shown
+	validating        bool                 // Whether the deck legality panel is shown
+	cardReturn        Mode                 // Mode CardMode returns to
+	notice            string               // Message shown until the next key press
+	banlists          []*banlist.List      // Forbidden/Limited lists to choose from
+	banlist           *banlist.List        // Active Forbidden/Limited list, if any
+	favorites         *favorites.Store     // Starred cards, if they can be stored
+	favoritesList     []favorites.Favorite // Favorites listed in FavoritesMode
+	favoritesSelected int                  // Selected favorite index in FavoritesMode
+	favoritesTag      string               // Tag the favorites are listed by, if any
+	favoritesOrder    favorites.Order      // Order the favorites are listed in
+	favoritesReturn   Mode                 // Mode FavoritesMode returns to
+	exportDir         string               // Directory exported files are written to
+	exportFormat      *format.Format       // Format search results are exported in
+	history           *history.Store       // Search history, if it can be stored
+	historyIndex      int                  // Recalled history entry in SearchMode from 1, or 0
+	historyDraft      string               // Input typed before recalling the history
+	historyQuery      string               // Query whose result count is pending
+	historyList       []history.Entry      // History entries listed in HistoryMode
+	historySelected   int                  // Selected entry index in HistoryMode
 }
 
-// NewModel creates a new UI model
-func NewModel() Model {
+// Options configures the UI
+type Options struct {
+	// Region limits the results to cards available in a region
+	Region cardinfo.Region
+	// NameLang is the preferred language of card names
+	NameLang api.NameLang
+	// DeckDir is the directory decks are saved in; saving is disabled if empty
+	DeckDir string
+	// Banlists are the Forbidden/Limited lists to choose from
+	Banlists []*banlist.List
+	// Banlist is the active Forbidden/Limited list, if any
+	Banlist *banlist.List
+	// Favorites holds the starred cards; starring is disabled if nil
+	Favorites *favorites.Store
+	// ExportDir is the directory exported files are written to
+	ExportDir string
+	// ExportFormat is the format search results are exported in
+	ExportFormat *format.Format
+	// History holds the search history; it is not kept if nil
+	History *history.Store
+	// PageSize is the number of items to display per page, or
+	// DefaultPageSize if zero
+	PageSize int
+	// InputCharLimit is the maximum length of the search input, or
+	// DefaultInputCharLimit if zero
+	InputCharLimit int
+	// InputWidth is the width of the search input, or DefaultInputWidth if
+	// zero
+	InputWidth int
+}
+
+// NewModel creates a new UI model backed by the given card source
+func NewModel(source api.CardSource, opts Options) Model {
 	ti := textinput.New()
 	ti.Placeholder = "输入卡片名称或ID"
 	ti.Focus()
-	ti.CharLimit = 156
-	ti.Width = 40
+	ti.CharLimit = valueOr(opts.InputCharLimit, DefaultInputCharLimit)
+	ti.Width = valueOr(opts.InputWidth, DefaultInputWidth)
+
+	pi := textinput.New()
+	pi.CharLimit = 64
+	pi.Width = ti.Width
 
 	return Model{
-		textInput:   ti,
-		results:     []api.Card{},
-		currentPage: 0,
-		totalPages:  0,
-		card:        nil,
-		selected:    -1,
-		err:         nil,
-		mode:        SearchMode,
-		loading:     false,
-		apiClient:   api.NewClient(),
-		query:       "",
-		nextStart:   0,
+		textInput:    ti,
+		rawResults:   []api.Card{},
+		results:      []api.Card{},
+		currentPage:  0,
+		totalPages:   0,
+		pageSize:     valueOr(opts.PageSize, DefaultPageSize),
+		card:         nil,
+		selected:     -1,
+		err:          nil,
+		mode:         SearchMode,
+		loading:      false,
+		source:       source,
+		query:        "",
+		nextStart:    0,
+		region:       opts.Region,
+		nameLang:     opts.NameLang,
+		deck:         deck.New(""),
+		deckDir:      opts.DeckDir,
+		promptInput:  pi,
+		cardReturn:   ResultMode,
+		banlists:     opts.Banlists,
+		banlist:      opts.Banlist,
+		favorites:    opts.Favorites,
+		exportDir:    opts.ExportDir,
+		exportFormat: opts.ExportFormat,
+		history:      opts.History,
+	}
+}
+
+// valueOr returns value, or def if value is zero
+func valueOr(value, def int) int {
+	if value == 0 {
+		return def
 	}
+	return value
 }
 
 // Init initializes the model
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"ygocdb-tui/internal/log"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// promptKind is what the prompt input asks for
+type promptKind int
+
+const (
+	// promptNone hides the prompt
+	promptNone promptKind = iota
+	// promptDeckName asks for the name to save the deck under
+	promptDeckName
+	// promptTags asks for the tags of a favorite
+	promptTags
+	// promptTagFilter asks for the tag to list favorites by
+	promptTagFilter
+)
+
+// label returns the text shown above the prompt input
+func (k promptKind) label() string {
+	switch k {
+	case promptDeckName:
+		return "保存为:"
+	case promptTags:
+		return "标签 (用逗号或空格分隔):"
+	case promptTagFilter:
+		return "按标签筛选 (留空显示全部):"
+	}
+	return ""
+}
+
+// placeholder returns the placeholder of the prompt input
+func (k promptKind) placeholder() string {
+	switch k {
+	case promptDeckName:
+		return "输入卡组名称"
+	case promptTags:
+		return "如: 手坑 泛用"
+	case promptTagFilter:
+		return "输入标签"
+	}
+	return ""
+}
+
+// startPrompt shows the prompt input with an initial value
+func (m *Model) startPrompt(kind promptKind, value string) tea.Cmd {
+	log.Debug("Prompting for input of kind %d", kind)
+	m.prompt = kind
+	m.promptInput.Placeholder = kind.placeholder()
+	m.promptInput.SetValue(value)
+	m.promptInput.CursorEnd()
+	return m.promptInput.Focus()
+}
+
+// endPrompt hides the prompt input
+func (m *Model) endPrompt() {
+	m.prompt = promptNone
+	m.promptInput.Blur()
+}
+
+// updatePrompt handles a key while the prompt input is shown
+func (m Model) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.endPrompt()
+		return m, nil
+		
+	case tea.KeyEnter:
+		value := m.promptInput.Value()
+		done := true
+		switch m.prompt {
+		case promptDeckName:
+			done = m.saveDeck(value)
+		case promptTags:
+			m.setFavoriteTags(value)
+		case promptTagFilter:
+			m.filterFavorites(value)
+		}
+		if done {
+			m.endPrompt()
+		}
+		return m, nil
+	}
+	
+	var cmd tea.Cmd
+	m.promptInput, cmd = m.promptInput.Update(msg)
+	return m, cmd
+}
+
+// formatPrompt formats the prompt input, if shown
+func (m *Model) formatPrompt() string {
+	if m.prompt == promptNone {
+		return ""
+	}
+	return m.prompt.label() + "\n" + inputStyle.Render(m.promptInput.View()) + "\n\n"
+}
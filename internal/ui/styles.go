@@ -2,41 +2,108 @@ package ui
 
 import "github.com/charmbracelet/lipgloss"
 
+// Colors are the colors of the UI, as hex codes like "#7D56F4" or ANSI
+// color numbers like "63"
+type Colors struct {
+	// Title is the text color of titles
+	Title string
+	// TitleBackground is the background color of titles
+	TitleBackground string
+	// Accent is the color of borders and selected lines
+	Accent string
+	// Help is the color of help text and pagination
+	Help string
+}
+
+// DefaultColors are the colors used unless others are set with Use
+var DefaultColors = Colors{
+	Title:           "#FAFAFA",
+	TitleBackground: "#7D56F4",
+	Accent:          "#7D56F4",
+	Help:            "#626262",
+}
+
 var (
 	// appStyle is the base style for the application
 	appStyle = lipgloss.NewStyle().Padding(1, 2)
 	
 	// titleStyle is the style for titles
+	titleStyle lipgloss.Style
+			
+	// inputStyle is the style for input fields
+	inputStyle lipgloss.Style
+			
+	// resultStyle is the style for search results
+	resultStyle lipgloss.Style
+			
+	// selectedStyle is the style for the selected line of a list
+	selectedStyle lipgloss.Style
+			
+	// cardStyle is the style for card details
+	cardStyle lipgloss.Style
+			
+	// paginationStyle is the style for pagination information
+	paginationStyle lipgloss.Style
+			
+	// helpStyle is the style for help text
+	helpStyle func(...string) string
+)
+
+func init() {
+	DefaultColors.Use()
+}
+
+// Use styles the UI with the colors, keeping the default of any color that
+// is empty
+func (c Colors) Use() {
+	c = c.withDefaults()
+	
 	titleStyle = lipgloss.NewStyle().
 			Bold(true).
-			Foreground(lipgloss.Color("#FAFAFA")).
-			Background(lipgloss.Color("#7D56F4")).
+			Foreground(lipgloss.Color(c.Title)).
+			Background(lipgloss.Color(c.TitleBackground)).
 			Padding(0, 1)
 			
-	// inputStyle is the style for input fields
 	inputStyle = lipgloss.NewStyle().
 			Border(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("#7D56F4")).
+			BorderForeground(lipgloss.Color(c.Accent)).
 			Padding(0, 1)
 			
-	// resultStyle is the style for search results
 	resultStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#7D56F4")).
+			BorderForeground(lipgloss.Color(c.Accent)).
 			Padding(1, 2)
 			
-	// cardStyle is the style for card details
+	selectedStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color(c.Accent))
+			
 	cardStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("#7D56F4")).
+			BorderForeground(lipgloss.Color(c.Accent)).
 			Padding(1, 2)
 			
-	// paginationStyle is the style for pagination information
 	paginationStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#626262")).
+			Foreground(lipgloss.Color(c.Help)).
 			Padding(1, 0)
 			
-	// helpStyle is the style for help text
 	helpStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#626262")).Render
-)
\ No newline at end of file
+			Foreground(lipgloss.Color(c.Help)).Render
+}
+
+// withDefaults returns the colors with empty colors set to their defaults
+func (c Colors) withDefaults() Colors {
+	if c.Title == "" {
+		c.Title = DefaultColors.Title
+	}
+	if c.TitleBackground == "" {
+		c.TitleBackground = DefaultColors.TitleBackground
+	}
+	if c.Accent == "" {
+		c.Accent = DefaultColors.Accent
+	}
+	if c.Help == "" {
+		c.Help = DefaultColors.Help
+	}
+	return c
+}
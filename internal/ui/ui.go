@@ -1,14 +1,15 @@
 package ui
 
 import (
+	"ygocdb-tui/internal/api"
 	"ygocdb-tui/internal/log"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
-// Start initializes and starts the TUI application
-func Start() error {
+// Start initializes and starts the TUI application using the given card source
+func Start(source api.CardSource, opts Options) error {
 	log.Info("Starting TUI application")
-	p := tea.NewProgram(NewModel())
+	p := tea.NewProgram(NewModel(source, opts))
 	_, err := p.Run()
 	
 	if err != nil {
@@ -1,9 +1,14 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/cardinfo"
+	"ygocdb-tui/internal/deck"
 	"ygocdb-tui/internal/log"
+	"ygocdb-tui/internal/query"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
@@ -16,16 +21,99 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		log.Debug("Processing key message: %v", msg)
+		m.notice = ""
+		if m.mode == ResultMode || m.mode == CardMode {
+			// Errors of loading a page or a card are shown like notices
+			m.err = nil
+		}
+		if msg.String() != m.discardKey {
+			m.discardKey = ""
+		}
+		if m.prompt != promptNone {
+			return m.updatePrompt(msg)
+		}
+		if m.mode == HistoryMode {
+			return m.updateHistory(msg)
+		}
 		
 		switch msg.Type {
+		case tea.KeyCtrlD:
+			// Toggle the deck builder
+			if m.loading {
+				return m, nil
+			}
+			if m.mode == DeckMode || m.mode == DeckListMode {
+				m.closeDeckMode()
+			} else if m.mode == SearchMode || m.mode == ResultMode || m.mode == CardMode {
+				m.openDeckMode()
+			}
+			return m, nil
+			
+		case tea.KeyCtrlR:
+			// Open the search history
+			if m.mode == SearchMode && !m.loading {
+				return m, m.openHistoryMode()
+			}
+			return m, nil
+			
+		case tea.KeyCtrlF:
+			// Toggle the favorites screen
+			if m.loading {
+				return m, nil
+			}
+			if m.mode == FavoritesMode {
+				m.closeFavoritesMode()
+			} else if m.mode == SearchMode || m.mode == ResultMode || m.mode == CardMode {
+				m.openFavoritesMode()
+			}
+			return m, nil
+			
 		case tea.KeyCtrlC, tea.KeyEsc:
+			if msg.Type == tea.KeyEsc && m.loading {
+				log.Info("Cancelling in-flight request")
+				m.cancelRequest()
+				m.loading = false
+				if m.mode == SearchMode {
+					m.textInput.Focus()
+				}
+				return m, nil
+			}
 			if m.mode == SearchMode {
+				label := "Esc"
+				if msg.Type == tea.KeyCtrlC {
+					label = "Ctrl+C"
+				}
+				if !m.confirmDiscard(msg.String(), label) {
+					return m, nil
+				}
+				m.cancelRequest()
 				log.Info("Received exit key, quitting application")
 				return m, tea.Quit
+			} else if m.mode == ArchetypeMode {
+				log.Info("Returning to card details")
+				m.mode = CardMode
+				m.err = nil
+				return m, nil
+			} else if m.mode == DeckMode {
+				m.closeDeckMode()
+				return m, nil
+			} else if m.mode == DeckListMode {
+				m.mode = DeckMode
+				return m, nil
+			} else if m.mode == FavoritesMode {
+				m.closeFavoritesMode()
+				return m, nil
+			} else if m.mode == CardMode && (m.cardReturn == DeckMode || m.cardReturn == FavoritesMode) {
+				log.Info("Returning from card details")
+				m.returnFromCard()
+				return m, nil
 			} else if m.mode == ResultMode || m.mode == CardMode {
 				log.Info("Returning to search mode")
+				m.cancelRequest()
+				m.loading = false
 				// Go back to search mode
 				m.mode = SearchMode
+				m.rawResults = []api.Card{}
 				m.results = []api.Card{}
 				m.card = nil
 				m.selected = -1
@@ -39,35 +127,61 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.KeyEnter:
 			if m.mode == SearchMode && !m.loading {
 				// Search for cards
-				query := m.textInput.Value()
-				if query != "" {
-					log.Info("Initiating search for query: %s", query)
-					m.query = query
-					m.currentPage = 0
-					m.loading = true
-					m.textInput.Blur()
-					return m, searchCardsCmd(query, 0)
+				if input := m.textInput.Value(); input != "" {
+					return m, m.searchCmd(input)
 				}
 			} else if m.mode == ResultMode && len(m.results) > 0 {
 				// View selected card
 				// Calculate the actual index in the full results array
-				actualIndex := m.currentPage*PageSize + m.selected
+				actualIndex := m.currentPage*m.pageSize + m.selected
 				if actualIndex >= 0 && actualIndex < len(m.results) {
 					log.Info("Viewing card details for card ID: %d", m.results[actualIndex].ID)
 					m.loading = true
-					return m, getCardByIDCmd(m.results[actualIndex].ID)
+					return m, getCardByIDCmd(m.newRequestContext(), m.source, m.results[actualIndex].ID)
 				}
+			} else if m.mode == ArchetypeMode && !m.loading && len(m.archetypes) > 0 {
+				return m, m.browseArchetypeCmd(m.archetypes[m.archetypeSelected])
 			} else if m.mode == CardMode {
-				// Back to results
-				log.Info("Returning to search results")
-				m.mode = ResultMode
-				m.card = nil
+				// Back to results, or to the deck the card was opened from
+				log.Info("Returning from card details")
+				m.returnFromCard()
 				m.loading = false
 				return m, nil
+			} else if m.mode == DeckMode {
+				// View the selected card of the deck
+				if entries := m.deckEntries(); m.deckSelected < len(entries) {
+					m.card = &api.GetCardResponse{Card: entries[m.deckSelected].Card}
+					m.cardReturn = DeckMode
+					m.mode = CardMode
+				}
+				return m, nil
+			} else if m.mode == DeckListMode && !m.loading && len(m.decks) > 0 {
+				return m, m.openSavedDeck()
+			} else if m.mode == FavoritesMode && len(m.favoritesList) > 0 {
+				// View the selected favorite
+				m.card = &api.GetCardResponse{Card: m.favoritesList[m.favoritesSelected].Card}
+				m.cardReturn = FavoritesMode
+				m.mode = CardMode
+				return m, nil
+			}
+
+		case tea.KeyRunes:
+			if cmd, handled := m.handleRunes(string(msg.Runes)); handled {
+				return m, cmd
 			}
 
 		case tea.KeyUp:
-			if m.mode == ResultMode && len(m.getCurrentPageResults()) > 0 {
+			if m.mode == SearchMode && !m.loading {
+				m.recallHistory(1)
+			} else if m.mode == ArchetypeMode && len(m.archetypes) > 0 {
+				m.archetypeSelected = (m.archetypeSelected + len(m.archetypes) - 1) % len(m.archetypes)
+			} else if n := len(m.deckEntries()); m.mode == DeckMode && n > 0 {
+				m.deckSelected = (m.deckSelected + n - 1) % n
+			} else if m.mode == DeckListMode && len(m.decks) > 0 {
+				m.deckListSelected = (m.deckListSelected + len(m.decks) - 1) % len(m.decks)
+			} else if n := len(m.favoritesList); m.mode == FavoritesMode && n > 0 {
+				m.favoritesSelected = (m.favoritesSelected + n - 1) % n
+			} else if m.mode == ResultMode && len(m.getCurrentPageResults()) > 0 {
 				m.selected--
 				if m.selected < 0 {
 					m.selected = len(m.getCurrentPageResults()) - 1
@@ -77,7 +191,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case tea.KeyDown:
-			if m.mode == ResultMode && len(m.getCurrentPageResults()) > 0 {
+			if m.mode == SearchMode && !m.loading {
+				m.recallHistory(-1)
+			} else if m.mode == ArchetypeMode && len(m.archetypes) > 0 {
+				m.archetypeSelected = (m.archetypeSelected + 1) % len(m.archetypes)
+			} else if n := len(m.deckEntries()); m.mode == DeckMode && n > 0 {
+				m.deckSelected = (m.deckSelected + 1) % n
+			} else if m.mode == DeckListMode && len(m.decks) > 0 {
+				m.deckListSelected = (m.deckListSelected + 1) % len(m.decks)
+			} else if n := len(m.favoritesList); m.mode == FavoritesMode && n > 0 {
+				m.favoritesSelected = (m.favoritesSelected + 1) % n
+			} else if m.mode == ResultMode && len(m.getCurrentPageResults()) > 0 {
 				m.selected++
 				if m.selected >= len(m.getCurrentPageResults()) {
 					m.selected = 0
@@ -104,17 +228,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 
 	case SearchResultMsg:
+		if !m.loading {
+			log.Debug("Ignoring search results of a cancelled request")
+			return m, nil
+		}
 		log.Info("Received search results message, found %d results", len(msg.Results.Result))
 		m.loading = false
 		m.mode = ResultMode
+		m.cardReturn = ResultMode
+		m.query = msg.Query
+		// A first page replaces the cached results
+		if msg.Start == 0 {
+			m.rawResults = []api.Card{}
+			m.currentPage = 0
+		}
 		// Append new results to cached results
-		m.results = append(m.results, msg.Results.Result...)
+		m.rawResults = append(m.rawResults, msg.Results.Result...)
+		m.results = m.filterResults(m.rawResults)
 		// Update pagination info
 		m.nextStart = msg.Results.Next
-		m.totalPages = (len(m.results) + PageSize - 1) / PageSize
+		m.totalPages = (len(m.results) + m.pageSize - 1) / m.pageSize
+		if msg.Start == 0 {
+			m.recordCount(len(m.results), m.nextStart > 0)
+		}
 		// Reset selection
 		m.selected = 0
-		if len(m.results) == 0 {
+		if len(m.results) == 0 && m.nextStart <= 0 {
 			m.err = fmt.Errorf("未找到相关卡片")
 			log.Warn("No results found for search")
 		} else {
@@ -124,22 +263,51 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case SearchByIDResultMsg:
+		if !m.loading {
+			log.Debug("Ignoring card result of a cancelled request")
+			return m, nil
+		}
 		log.Info("Received card by ID result message, card ID: %d", msg.Card.ID)
 		m.loading = false
+		m.recordCount(1, false)
 		m.mode = CardMode
+		m.cardReturn = ResultMode
 		m.card = msg.Card
 		return m, nil
 
 	case CardResultMsg:
+		if !m.loading {
+			log.Debug("Ignoring card result of a cancelled request")
+			return m, nil
+		}
 		log.Info("Received card result message, card ID: %d", msg.Card.ID)
 		m.loading = false
 		m.mode = CardMode
+		m.cardReturn = ResultMode
 		m.card = msg.Card
 		return m, nil
 
+	case DeckResultMsg:
+		if !m.loading {
+			log.Debug("Ignoring deck of a cancelled request")
+			return m, nil
+		}
+		log.Info("Received deck result message, deck: %s", msg.Deck.Name)
+		m.loading = false
+		m.setDeck(msg.Deck)
+		if msg.Err != nil {
+			m.notice = fmt.Sprintf("部分卡片未能识别: %v", msg.Err)
+		}
+		return m, nil
+		
 	case SearchErrorMsg:
+		if errors.Is(msg.Err, context.Canceled) || !m.loading {
+			log.Debug("Ignoring error of a cancelled request: %v", msg.Err)
+			return m, nil
+		}
 		log.Error("Received search error message: %v", msg.Err)
 		m.loading = false
+		m.recordError(msg.Err)
 		m.err = msg.Err
 		m.textInput.Focus()
 		return m, nil
@@ -156,10 +324,209 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// searchCmd parses the input and starts searching for the cards it matches
+func (m *Model) searchCmd(input string) tea.Cmd {
+	if deck.IsYDKE(input) {
+		return m.openYDKECmd(input)
+	}
+	
+	q, err := query.Parse(input)
+	if err != nil {
+		log.Warn("Invalid query %q: %v", input, err)
+		m.err = err
+		return nil
+	}
+	m.recordSearch(input)
+	
+	log.Info("Initiating search for query: %s, filter: %v", q.Search, q.Filter)
+	m.err = nil
+	m.filter = q.Filter
+	m.currentPage = 0
+	
+	if q.Search == "" {
+		if q.Filter == nil {
+			return nil
+		}
+		// Only local sources can list cards by card data alone
+		filterSource, ok := m.source.(api.FilterSource)
+		if !ok {
+			m.err = fmt.Errorf("在线搜索需要至少一个关键词，例如: 龙 attr:暗 level>=8")
+			return nil
+		}
+		m.loading = true
+		m.textInput.Blur()
+		return filterCardsCmd(m.newRequestContext(), filterSource, q.Filter)
+	}
+	
+	m.query = q.Search
+	m.loading = true
+	m.textInput.Blur()
+	// Numbers are passcodes unless combined with other terms
+	if id, ok := q.CardID(); ok {
+		return searchCardByIDCmd(m.newRequestContext(), m.source, id)
+	}
+	return searchCardsCmd(m.newRequestContext(), m.source, q.Search, 0)
+}
+
+// handleRunes handles a key that types characters outside of SearchMode. It
+// reports whether the key was handled; unhandled keys go to the text input.
+func (m *Model) handleRunes(key string) (tea.Cmd, bool) {
+	if m.mode == SearchMode || m.loading {
+		return nil, false
+	}
+	if key == "b" && (m.mode == ResultMode || m.mode == CardMode || m.mode == DeckMode) {
+		m.cycleBanlist()
+		return nil, true
+	}
+	if m.mode == DeckMode || m.mode == DeckListMode {
+		return m.handleDeckRunes(key)
+	}
+	if m.mode == FavoritesMode {
+		return m.handleFavoritesRunes(key)
+	}
+	
+	switch {
+	case key == "d" && (m.mode == ResultMode || m.mode == CardMode):
+		// Add the card to the main or extra deck
+		m.addToDeck(false)
+		return nil, true
+		
+	case key == "s" && (m.mode == ResultMode || m.mode == CardMode):
+		// Add the card to the side deck
+		m.addToDeck(true)
+		return nil, true
+		
+	case key == "f" && (m.mode == ResultMode || m.mode == CardMode):
+		// Star or unstar the card
+		m.toggleFavorite()
+		return nil, true
+		
+
+	case key == "e" && m.mode == ResultMode:
+		// Export the fetched results
+		m.exportResults()
+		return nil, true
+		
+	case key == "t" && m.mode == ResultMode:
+		// Cycle the export format
+		m.cycleExportFormat()
+		return nil, true
+		
+	case key == "r" && m.mode == ResultMode:
+		// Cycle the region filter
+		m.region = m.region.Next()
+		log.Info("Region filter changed to %s", m.region)
+		m.refilterResults()
+		return m.autoFetchNextPageCmd(), true
+		
+	case key == "l" && (m.mode == ResultMode || m.mode == CardMode):
+		// Cycle the name language
+		m.nameLang = m.nameLang.Next()
+		log.Info("Name language changed to %s", m.nameLang)
+		return nil, true
+		
+	case key == "a" && m.mode == CardMode && m.card != nil:
+		// Browse the archetypes of the card, without the filter of the last search
+		m.filter = nil
+		m.archetypes = cardinfo.Setcodes(m.card.Data.Setcode)
+		m.archetypeSelected = 0
+		switch len(m.archetypes) {
+		case 0:
+			log.Debug("Card %d has no archetype", m.card.ID)
+			return nil, true
+		case 1:
+			return m.browseArchetypeCmd(m.archetypes[0]), true
+		default:
+			log.Info("Choosing among %d archetypes", len(m.archetypes))
+			m.mode = ArchetypeMode
+			return nil, true
+		}
+	}
+	
+	return nil, false
+}
+
+// cycleBanlist activates the next Forbidden/Limited list, or none after the
+// last one
+func (m *Model) cycleBanlist() {
+	if len(m.banlists) == 0 {
+		m.notice = "未加载禁限卡表，请使用 -lflist 指定 lflist.conf"
+		return
+	}
+	next := 0
+	for i, list := range m.banlists {
+		if list == m.banlist {
+			next = i + 1
+		}
+	}
+	if next < len(m.banlists) {
+		m.banlist = m.banlists[next]
+	} else {
+		m.banlist = nil
+	}
+	log.Info("Banlist changed to %s", m.banlistLabel())
+}
+
+// banlistLabel returns the name of the active Forbidden/Limited list
+func (m *Model) banlistLabel() string {
+	if m.banlist == nil {
+		return "无"
+	}
+	return m.banlist.Name
+}
+
+// returnFromCard leaves CardMode for the mode the card was opened from
+func (m *Model) returnFromCard() {
+	m.mode = m.cardReturn
+	m.cardReturn = ResultMode
+	m.card = nil
+	if m.mode == FavoritesMode {
+		// The card may have been unstarred
+		m.refreshFavorites()
+	}
+}
+
+// browseArchetypeCmd replaces the results with the cards of an archetype
+func (m *Model) browseArchetypeCmd(archetype int) tea.Cmd {
+	log.Info("Browsing archetype %#x", archetype)
+	m.err = nil
+	m.loading = true
+	return archetypeCardsCmd(m.newRequestContext(), m.source, archetype)
+}
+
+// filterResults returns the results available in the selected region that
+// match the query filter
+func (m *Model) filterResults(results []api.Card) []api.Card {
+	filtered := make([]api.Card, 0, len(results))
+	for _, card := range results {
+		if !m.region.Allows(cardinfo.OT(card.Data.OT)) {
+			continue
+		}
+		if m.filter != nil && !m.filter.Match(&card) {
+			continue
+		}
+		filtered = append(filtered, card)
+	}
+	return filtered
+}
+
+// refilterResults reapplies the filters to the fetched results, keeping the
+// current page and selection in range
+func (m *Model) refilterResults() {
+	m.results = m.filterResults(m.rawResults)
+	m.totalPages = (len(m.results) + m.pageSize - 1) / m.pageSize
+	if m.currentPage >= m.totalPages {
+		m.currentPage = max(m.totalPages-1, 0)
+	}
+	if m.selected >= len(m.getCurrentPageResults()) {
+		m.selected = 0
+	}
+}
+
 // getCurrentPageResults returns the results for the current page
 func (m *Model) getCurrentPageResults() []api.Card {
-	start := m.currentPage * PageSize
-	end := start + PageSize
+	start := m.currentPage * m.pageSize
+	end := start + m.pageSize
 	
 	// Ensure end doesn't exceed the total number of results
 	if end > len(m.results) {
@@ -1,14 +1,57 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/banlist"
+	"ygocdb-tui/internal/cardinfo"
 )
 
-// formatCardSummary formats a card summary for display
-func formatCardSummary(card api.Card) string {
-	return fmt.Sprintf("%s (%d)", card.CnName, card.ID)
+// FormatCardSummary formats a card summary for display, with its limit
+// status on the banlist if one is given
+func FormatCardSummary(card api.Card, lang api.NameLang, list *banlist.List) string {
+	name := card.DisplayName(lang)
+	if name == "" {
+		// Cards of a deck that could not be looked up
+		name = "未知卡片"
+	}
+	summary := fmt.Sprintf("%s (%d)", name, card.ID)
+	if badge := cardinfo.OT(card.Data.OT).Badge(); badge != "" {
+		summary += " [" + badge + "]"
+	}
+	if list != nil {
+		if badge := list.Status(card.BaseID()).Badge(); badge != "" {
+			summary += " [" + badge + "]"
+		}
+	}
+	return summary
+}
+
+// DescribeError returns an actionable message for an error
+func DescribeError(err error) string {
+	var statusErr *api.StatusError
+	switch {
+	case errors.Is(err, api.ErrNotFound):
+		return "未找到该卡片，请检查卡片密码是否正确"
+	case errors.Is(err, api.ErrRateLimited):
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			return fmt.Sprintf("请求过于频繁，请在 %s 后重试", statusErr.RetryAfter)
+		}
+		return "请求过于频繁，请稍后重试"
+	case errors.Is(err, api.ErrServer):
+		return "百鸽服务器暂时不可用，请稍后重试"
+	case errors.Is(err, api.ErrDecode):
+		return "无法解析服务器返回的数据，API 可能已变更，请尝试更新本程序"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "请求超时，请检查网络连接或使用 -timeout 增加超时时间"
+	case errors.Is(err, api.ErrNetwork):
+		return "网络连接失败，请检查网络连接后重试"
+	default:
+		return err.Error()
+	}
 }
 
 // formatPagination formats pagination information for display
@@ -25,25 +68,47 @@ func formatPagination(currentPage, totalPages int) string {
 	}
 }
 
-// formatCardDetails formats card details for display
-func formatCardDetails(card api.GetCardResponse) string {
+// FormatCardDetails formats card details for display, with its limit status
+// on the banlist if one is given
+func FormatCardDetails(card api.GetCardResponse, lang api.NameLang, list *banlist.List) string {
 	var b strings.Builder
 	b.WriteString(fmt.Sprintf("卡片密码: %d\n", card.ID))
-	b.WriteString(fmt.Sprintf("名称: %s\n", card.Text.Name))
-	b.WriteString(fmt.Sprintf("类型: %s\n", getCardType(card.Data.Type)))
-	b.WriteString(fmt.Sprintf("种族: %s\n", getCardRace(card.Data.Race)))
-	b.WriteString(fmt.Sprintf("属性: %s\n", getCardAttribute(card.Data.Attrib)))
-
-	if card.Data.Level > 0 {
-		b.WriteString(fmt.Sprintf("星级: %d\n", card.Data.Level))
+	if card.Alias != 0 {
+		b.WriteString(fmt.Sprintf("异画: 原卡密码 %d\n", card.Alias))
+	}
+	b.WriteString(fmt.Sprintf("名称: %s\n", card.DisplayName(lang)))
+	if aliases := card.Aliases(); len(aliases) > 1 {
+		b.WriteString("别名:\n")
+		for _, alias := range aliases {
+			b.WriteString(fmt.Sprintf("  %s: %s\n", alias.Lang.Label(), alias.Name))
+		}
+	}
+	if card.JpName != "" && card.JpRuby != "" {
+		b.WriteString(formatRuby(card.JpName, card.JpRuby))
+	}
+	if badge := cardinfo.OT(card.Data.OT).Badge(); badge != "" {
+		b.WriteString(fmt.Sprintf("地区: %s\n", badge))
+	}
+	if list != nil {
+		b.WriteString(fmt.Sprintf("禁限: %s (%s)\n", list.Status(card.BaseID()), list.Name))
+	}
+	typ := cardinfo.Type(card.Data.Type)
+	b.WriteString(fmt.Sprintf("类型: %s\n", typ))
+	if codes := cardinfo.Setcodes(card.Data.Setcode); len(codes) > 0 {
+		b.WriteString(fmt.Sprintf("系列: %s\n", formatArchetypes(codes)))
+	}
+	if typ.IsMonster() {
+		b.WriteString(fmt.Sprintf("种族: %s\n", cardinfo.Race(card.Data.Race)))
+		b.WriteString(fmt.Sprintf("属性: %s\n", cardinfo.Attribute(card.Data.Attrib)))
+		b.WriteString(formatMonsterStats(typ, card.Data))
 	}
 
-	if card.Data.Atk >= 0 {
-		b.WriteString(fmt.Sprintf("攻击力: %d\n", card.Data.Atk))
+	if counters := cardinfo.CountersIn(card.Text.PDesc + card.Text.Desc); len(counters) > 0 {
+		b.WriteString(fmt.Sprintf("指示物: %s\n", formatCounters(counters)))
 	}
 
-	if card.Data.Def >= 0 {
-		b.WriteString(fmt.Sprintf("守备力: %d\n", card.Data.Def))
+	if card.Text.PDesc != "" {
+		b.WriteString(fmt.Sprintf("\n灵摆效果:\n%s\n", card.Text.PDesc))
 	}
 
 	b.WriteString(fmt.Sprintf("\n效果:\n%s", card.Text.Desc))
@@ -51,96 +116,63 @@ func formatCardDetails(card api.GetCardResponse) string {
 	return b.String()
 }
 
-// getCardType returns the Chinese name for a card type
-func getCardType(typ int) string {
-	switch typ {
-	case 17:
-		return "怪兽|通常"
-	case 33:
-		return "怪兽|效果"
-	case 65:
-		return "魔法|通常"
-	case 129:
-		return "陷阱|通常"
-	default:
-		return fmt.Sprintf("未知类型(%d)", typ)
+// formatRuby formats the Japanese name with its reading above it
+func formatRuby(name, ruby string) string {
+	segments := cardinfo.ParseRuby(name, ruby)
+	if !cardinfo.HasRuby(segments) {
+		return ""
 	}
+	top, bottom := cardinfo.RenderRuby(segments)
+	return fmt.Sprintf("读音:\n  %s\n  %s\n", top, bottom)
 }
 
-// getCardRace returns the Chinese name for a card race
-func getCardRace(race int) string {
-	switch race {
-	case 1:
-		return "战士"
-	case 2:
-		return "魔法师"
-	case 4:
-		return "天使"
-	case 8:
-		return "恶魔"
-	case 16:
-		return "不死"
-	case 32:
-		return "机械"
-	case 64:
-		return "水"
-	case 128:
-		return "炎"
-	case 256:
-		return "岩石"
-	case 512:
-		return "鸟兽"
-	case 1024:
-		return "植物"
-	case 2048:
-		return "昆虫"
-	case 4096:
-		return "雷"
-	case 8192:
-		return "龙"
-	case 16384:
-		return "兽"
-	case 32768:
-		return "兽战士"
-	case 65536:
-		return "恐龙"
-	case 131072:
-		return "鱼"
-	case 262144:
-		return "海龙"
-	case 524288:
-		return "爬虫类"
-	case 1048576:
-		return "念动力"
-	case 2097152:
-		return "幻神兽"
-	case 4194304:
-		return "创造神"
-	case 8388608:
-		return "幻龙"
-	default:
-		return fmt.Sprintf("未知种族(%d)", race)
+// formatArchetypes formats the names of archetype codes
+func formatArchetypes(codes []int) string {
+	names := make([]string, len(codes))
+	for i, code := range codes {
+		names[i] = cardinfo.ArchetypeName(code)
 	}
+	return strings.Join(names, " / ")
 }
 
-// getCardAttribute returns the Chinese name for a card attribute
-func getCardAttribute(attr int) string {
-	switch attr {
-	case 1:
-		return "地"
-	case 2:
-		return "水"
-	case 4:
-		return "炎"
-	case 8:
-		return "风"
-	case 16:
-		return "光"
-	case 32:
-		return "暗"
-	case 64:
-		return "神"
-	default:
-		return fmt.Sprintf("未知属性(%d)", attr)
+// formatCounters formats counter names with their codes
+func formatCounters(counters []cardinfo.Counter) string {
+	names := make([]string, len(counters))
+	for i, counter := range counters {
+		names[i] = fmt.Sprintf("%s (%#x)", counter.Name, counter.Code)
+	}
+	return strings.Join(names, " / ")
+}
+
+// formatMonsterStats formats the level, scales, ATK/DEF and link markers of
+// a monster
+func formatMonsterStats(typ cardinfo.Type, data api.Data) string {
+	var b strings.Builder
+	stats := cardinfo.DecodeStats(typ, data.Level, data.Atk, data.Def)
+
+	if stats.Level > 0 {
+		b.WriteString(fmt.Sprintf("%s: %d\n", cardinfo.LevelLabel(typ), stats.Level))
+	}
+
+	if typ.Has(cardinfo.TypePendulum) {
+		b.WriteString(fmt.Sprintf("灵摆刻度: ←%d %d→\n", stats.LeftScale, stats.RightScale))
+	}
+
+	b.WriteString(fmt.Sprintf("攻击力: %s\n", cardinfo.FormatStat(stats.Atk)))
+
+	if typ.Has(cardinfo.TypeLink) {
+		b.WriteString(fmt.Sprintf("连接标记:\n%s\n", stats.LinkMarkers.Diagram()))
+	} else {
+		b.WriteString(fmt.Sprintf("守备力: %s\n", cardinfo.FormatStat(stats.Def)))
+	}
+
+	return b.String()
+}
+
+// formatNotice formats the notice of the last key press, if any
+func (m *Model) formatNotice() string {
+	if m.notice == "" {
+		return ""
 	}
+	return m.notice + "\n\n"
 }
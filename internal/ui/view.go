@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"strings"
+	"ygocdb-tui/internal/cardinfo"
 	"ygocdb-tui/internal/log"
 )
 
@@ -22,23 +23,31 @@ func (m Model) View() string {
 		
 		if m.loading {
 			log.Debug("Showing loading indicator")
-			b.WriteString("搜索中...")
+			b.WriteString("搜索中... (按 Esc 取消)")
 		} else if m.err != nil {
 			log.Debug("Showing error message: %v", m.err)
-			b.WriteString(fmt.Sprintf("错误: %v\n\n", m.err))
+			b.WriteString(fmt.Sprintf("错误: %s\n\n", DescribeError(m.err)))
 			m.err = nil // Reset error after displaying
 		}
+		b.WriteString(m.formatNotice())
 		
-		b.WriteString(helpStyle("按 Enter 搜索，按 Esc 退出"))
+		b.WriteString(helpStyle("按 Enter 搜索，按 ↑/↓ 浏览搜索历史，按 Ctrl+R 搜索历史记录，按 Ctrl+D 打开卡组，按 Ctrl+F 打开收藏，按 Esc 退出\n支持条件搜索，如: attr:暗 type:synchro level>=8 atk>2500 \"破坏\"\n粘贴 ydke:// 链接可打开卡组"))
 		
 	case ResultMode:
 		log.Debug("Rendering result mode view, results count: %d, current page: %d", len(m.results), m.currentPage)
 		b.WriteString(titleStyle.Render("搜索结果"))
+		if m.region != cardinfo.RegionAll {
+			b.WriteString(" " + helpStyle("仅显示 "+m.region.String()+" 卡片"))
+		}
 		b.WriteString("\n\n")
 		
+		if !m.loading && m.err != nil {
+			log.Debug("Showing error message: %v", m.err)
+			b.WriteString(fmt.Sprintf("错误: %s\n\n", DescribeError(m.err)))
+		}
 		if m.loading {
 			log.Debug("Showing loading indicator")
-			b.WriteString("加载中...")
+			b.WriteString("加载中... (按 Esc 取消)")
 		} else if len(m.results) == 0 {
 			log.Debug("No results to display")
 			b.WriteString("未找到相关卡片")
@@ -48,10 +57,14 @@ func (m Model) View() string {
 			log.Debug("Displaying %d results on current page", len(currentPageResults))
 			
 			for i, result := range currentPageResults {
+				summary := FormatCardSummary(result, m.nameLang, m.banlist)
+				if m.isFavorite(result.ID) {
+					summary = "★ " + summary
+				}
 				if i == m.selected {
-					b.WriteString("> " + resultStyle.Render(formatCardSummary(result)) + "\n\n")
+					b.WriteString("> " + resultStyle.Render(summary) + "\n\n")
 				} else {
-					b.WriteString("  " + formatCardSummary(result) + "\n\n")
+					b.WriteString("  " + summary + "\n\n")
 				}
 			}
 			
@@ -61,23 +74,143 @@ func (m Model) View() string {
 		}
 		
 		b.WriteString("\n")
-		b.WriteString(helpStyle("使用 ↑/↓ 选择卡片，←/→ 翻页，按 Enter 查看详情，按 Esc 返回"))
+		b.WriteString(m.formatNotice())
+		b.WriteString(helpStyle("使用 ↑/↓ 选择卡片，←/→ 翻页，按 Enter 查看详情，按 r 切换地区，按 l 切换名称语言 (" + m.nameLang.Label() + ")，按 b 切换禁限卡表 (" + m.banlistLabel() + ")，按 Esc 返回\n按 d 加入主/额外卡组，按 s 加入副卡组，按 f 收藏，按 e 导出结果，按 t 切换导出格式 (" + m.exportFormat.Name + ")，按 Ctrl+D 打开卡组，按 Ctrl+F 打开收藏"))
 
 	case CardMode:
 		log.Debug("Rendering card mode view")
 		b.WriteString(titleStyle.Render("卡片详情"))
+		if m.card != nil && m.isFavorite(m.card.ID) {
+			b.WriteString(" ★")
+		}
 		b.WriteString("\n\n")
 		
 		if m.loading {
 			log.Debug("Showing loading indicator")
 			b.WriteString("加载中...")
-		} else if m.card != nil {
+		} else if m.err != nil {
+			log.Debug("Showing error message: %v", m.err)
+			b.WriteString(fmt.Sprintf("错误: %s\n\n", DescribeError(m.err)))
+		}
+		if !m.loading && m.card != nil {
 			log.Debug("Displaying card details for card ID: %d", m.card.ID)
-			b.WriteString(cardStyle.Render(formatCardDetails(*m.card)))
+			b.WriteString(cardStyle.Render(FormatCardDetails(*m.card, m.nameLang, m.banlist)))
 		}
 		
 		b.WriteString("\n\n")
-		b.WriteString(helpStyle("按 Enter 或 Esc 返回搜索结果"))
+		b.WriteString(m.formatNotice())
+		back := "搜索结果"
+		switch m.cardReturn {
+		case DeckMode:
+			back = "卡组"
+		case FavoritesMode:
+			back = "收藏"
+		}
+		b.WriteString(helpStyle("按 a 浏览同系列卡片，按 l 切换名称语言 (" + m.nameLang.Label() + ")，按 b 切换禁限卡表 (" + m.banlistLabel() + ")，按 Enter 或 Esc 返回" + back + "\n按 d 加入主/额外卡组，按 s 加入副卡组，按 f 收藏，按 Ctrl+D 打开卡组，按 Ctrl+F 打开收藏"))
+
+	case ArchetypeMode:
+		log.Debug("Rendering archetype mode view, archetypes count: %d", len(m.archetypes))
+		b.WriteString(titleStyle.Render("选择系列"))
+		b.WriteString("\n\n")
+		
+		if m.loading {
+			log.Debug("Showing loading indicator")
+			b.WriteString("加载中... (按 Esc 取消)\n\n")
+		} else if m.err != nil {
+			b.WriteString(fmt.Sprintf("错误: %s\n\n", DescribeError(m.err)))
+		}
+		
+		for i, archetype := range m.archetypes {
+			name := fmt.Sprintf("%s (%#x)", cardinfo.ArchetypeName(archetype), archetype)
+			if i == m.archetypeSelected {
+				b.WriteString("> " + resultStyle.Render(name) + "\n")
+			} else {
+				b.WriteString("  " + name + "\n")
+			}
+		}
+		
+		b.WriteString("\n")
+		b.WriteString(helpStyle("使用 ↑/↓ 选择系列，按 Enter 浏览该系列卡片，按 Esc 返回"))
+
+	case DeckMode:
+		log.Debug("Rendering deck mode view, deck size: %d", m.deck.Len())
+		title := "卡组"
+		if m.deck.Name != "" {
+			title += ": " + m.deck.Name
+		}
+		if m.deckModified {
+			title += " *"
+		}
+		b.WriteString(titleStyle.Render(title))
+		b.WriteString("\n\n")
+		b.WriteString(m.formatDeck())
+		if m.validating {
+			b.WriteString(cardStyle.Render(m.formatViolations()) + "\n\n")
+		} else if err := m.deck.SizeError(); err != nil {
+			b.WriteString(helpStyle(err.Error()) + "\n\n")
+		}
+		
+		if m.prompt != promptNone {
+			b.WriteString(m.formatPrompt())
+			b.WriteString(m.formatNotice())
+			b.WriteString(helpStyle("按 Enter 保存，按 Esc 取消"))
+			break
+		}
+		b.WriteString(m.formatNotice())
+		b.WriteString(helpStyle("使用 ↑/↓ 选择卡片，按 Enter 查看详情，按 x 移除一张，按 v 检查合法性，按 r 切换地区，按 w 保存，按 e 导出为 .ydk，按 y 生成 ydke 链接，按 o 打开已保存的卡组，按 n 新建卡组，按 b 切换禁限卡表 (" + m.banlistLabel() + ")，按 Esc 返回"))
+
+	case DeckListMode:
+		log.Debug("Rendering deck list view, decks count: %d", len(m.decks))
+		b.WriteString(titleStyle.Render("打开卡组"))
+		b.WriteString("\n\n")
+		
+		if m.loading {
+			b.WriteString("读取中... (按 Esc 取消)\n\n")
+		} else if m.err != nil {
+			b.WriteString(fmt.Sprintf("错误: %s\n\n", DescribeError(m.err)))
+		}
+		
+		for i, name := range m.decks {
+			if i == m.deckListSelected {
+				b.WriteString("> " + selectedStyle.Render(name) + "\n")
+			} else {
+				b.WriteString("  " + name + "\n")
+			}
+		}
+		
+		b.WriteString("\n")
+		b.WriteString(m.formatNotice())
+		b.WriteString(helpStyle("使用 ↑/↓ 选择卡组，按 Enter 打开，按 Esc 返回"))
+
+	case HistoryMode:
+		log.Debug("Rendering history view, entries count: %d", len(m.historyList))
+		b.WriteString(titleStyle.Render("搜索历史"))
+		b.WriteString("\n\n")
+		b.WriteString(inputStyle.Render(m.promptInput.View()))
+		b.WriteString("\n\n")
+		b.WriteString(m.formatHistory())
+		b.WriteString("\n")
+		b.WriteString(helpStyle("输入文字模糊筛选，使用 ↑/↓ 选择，按 Enter 重新搜索，按 Delete 删除记录，按 Esc 返回"))
+
+	case FavoritesMode:
+		log.Debug("Rendering favorites view, favorites count: %d", len(m.favoritesList))
+		title := "收藏"
+		if m.favoritesTag != "" {
+			title += " #" + m.favoritesTag
+		}
+		b.WriteString(titleStyle.Render(title))
+		b.WriteString(" " + helpStyle(fmt.Sprintf("%d 张，按%s排序", len(m.favoritesList), m.favoritesOrder)))
+		b.WriteString("\n\n")
+		b.WriteString(m.formatFavorites())
+		
+		if m.prompt != promptNone {
+			b.WriteString(m.formatPrompt())
+			b.WriteString(m.formatNotice())
+			b.WriteString(helpStyle("按 Enter 确认，按 Esc 取消"))
+			break
+		}
+		b.WriteString(m.formatNotice())
+		b.WriteString(helpStyle("使用 ↑/↓ 选择卡片，按 Enter 查看详情，按 x 取消收藏，按 t 编辑标签，按 f 按标签筛选，按 o 切换排序，按 e 导出，按 l 切换名称语言 (" + m.nameLang.Label() + ")，按 Esc 返回"))
 	}
 
 	view := appStyle.Render(b.String())
@@ -1,11 +1,27 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	stdlog "log"
 	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/banlist"
+	"ygocdb-tui/internal/cardinfo"
+	"ygocdb-tui/internal/cdb"
+	"ygocdb-tui/internal/config"
+	"ygocdb-tui/internal/dataset"
+	"ygocdb-tui/internal/deck"
+	"ygocdb-tui/internal/favorites"
+	"ygocdb-tui/internal/format"
+	"ygocdb-tui/internal/history"
+	"ygocdb-tui/internal/local"
 	"ygocdb-tui/internal/log"
+	"ygocdb-tui/internal/paths"
 	"ygocdb-tui/internal/ui"
 )
 
@@ -22,32 +38,64 @@ func (l *logLevel) String() string {
 
 // Set sets the log level from a string
 func (l *logLevel) Set(value string) error {
-	switch value {
-	case "off", "OFF":
-		l.value = log.OffLevel
-	case "error", "ERROR":
-		l.value = log.ErrorLevel
-	case "warn", "WARN":
-		l.value = log.WarnLevel
-	case "info", "INFO":
-		l.value = log.InfoLevel
-	case "debug", "DEBUG":
-		l.value = log.DebugLevel
-	default:
-		return fmt.Errorf("invalid log level: %s", value)
+	level, err := log.ParseLevel(value)
+	if err != nil {
+		return err
 	}
+	l.value = level
 	l.set = true
 	return nil
 }
 
+// stringList represents a flag that may be given multiple times
+type stringList []string
+
+// String returns the string representation of the list
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set appends a value to the list
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
 func main() {
 	// Define command line flags
 	var logLevelFlag logLevel
 	flag.Var(&logLevelFlag, "log-level", "set log level (off, error, warn, info, debug)")
+	logDir := flag.String("log-dir", log.DefaultDir, "directory log files are written to")
+	configPath := flag.String("config", "", "path of the config file (default: "+config.FileName+" in the user config directory)")
+	var cdbPaths stringList
+	flag.Var(&cdbPaths, "cdb", "serve cards offline from a YGOPro cards.cdb (may be repeated)")
+	var stringsPaths stringList
+	flag.Var(&stringsPaths, "strings", "load archetype and counter names from a YGOPro strings.conf (may be repeated)")
+	var lflistPaths stringList
+	flag.Var(&lflistPaths, "lflist", "load Forbidden/Limited lists from a YGOPro lflist.conf (may be repeated)")
+	banlistName := flag.String("banlist", "", "name of the active Forbidden/Limited list (default: the first list loaded)")
+	baseURL := flag.String("base-url", api.BaseURL, "base URL of the ygocdb API")
+	timeout := flag.Duration("timeout", api.DefaultTimeout, "timeout of a single API request")
+	cacheTTL := flag.Duration("cache-ttl", api.DefaultCacheTTL, "time cached API responses are used without revalidation")
+	noCache := flag.Bool("no-cache", false, "disable the on-disk API response cache")
+	retries := flag.Int("retries", api.DefaultMaxRetries, "number of retries for transient API failures")
+	region := flag.String("region", "all", "only show cards available in a region (all, ocg, tcg)")
+	nameLang := flag.String("lang", "cn", "preferred language of card names (cn, sc, md, nwbbs, cnocg, jp, en)")
+	online := flag.Bool("online", false, "always query the ygocdb API, even if a synced dataset is present")
+	datasetDir := flag.String("dataset-dir", "", "directory of the synced card dataset (default: dataset in the user data directory)")
+	outputFormat := flag.String("format", "", "output format of search, show and lookup, and of result exports ("+strings.Join(format.Names(), ", ")+")")
+	pageSize := flag.Int("page-size", ui.DefaultPageSize, "number of cards shown per page of search results")
 	
 	// Set usage message
 	flag.Usage = func() {
-		fmt.Fprintf(os.Stderr, "Usage: %s [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] [command]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Commands:\n")
+		fmt.Fprintf(os.Stderr, "  search    print the cards matching a query\n")
+		fmt.Fprintf(os.Stderr, "  show      print the details of cards by passcode\n")
+		fmt.Fprintf(os.Stderr, "  lookup    print a line per passcode given as arguments or on stdin (-)\n")
+		fmt.Fprintf(os.Stderr, "  sync      download the full card dataset for local search\n")
+		fmt.Fprintf(os.Stderr, "  ydk       print .ydk deck files and ydke:// links as card lists\n")
+		fmt.Fprintf(os.Stderr, "  validate  check decks against the active banlist and region\n\n")
 		fmt.Fprintf(os.Stderr, "Options:\n")
 		flag.PrintDefaults()
 	}
@@ -55,9 +103,23 @@ func main() {
 	// Parse command line flags
 	flag.Parse()
 	
+	// Load the config file; flags given on the command line override it
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		stdlog.Fatal(err)
+	}
+	if err := applyConfig(flag.CommandLine, cfg); err != nil {
+		stdlog.Fatal(err)
+	}
+	if *pageSize < 1 || *pageSize > config.MaxPageSize {
+		fmt.Fprintf(os.Stderr, "invalid page size: %d (expected 1 to %d)\n", *pageSize, config.MaxPageSize)
+		flag.Usage()
+		exit(2)
+	}
+	
 	// Initialize logger if log level is set
 	if logLevelFlag.set {
-		if err := log.Init(logLevelFlag.value); err != nil {
+		if err := log.Init(logLevelFlag.value, *logDir); err != nil {
 			stdlog.Printf("failed to initialize logger: %v", err)
 			os.Exit(1)
 		}
@@ -67,8 +129,155 @@ func main() {
 		log.Info("ygocdb-tui started with log level: %s", logLevelFlag.value.String())
 	}
 	
+	// Parse UI options
+	uiOpts := ui.Options{
+		PageSize:       *pageSize,
+		InputCharLimit: cfg.Input.CharLimit,
+		InputWidth:     cfg.Input.Width,
+	}
+	ui.Colors{
+		Title:           cfg.Colors.Title,
+		TitleBackground: cfg.Colors.TitleBackground,
+		Accent:          cfg.Colors.Accent,
+		Help:            cfg.Colors.Help,
+	}.Use()
+	if uiOpts.Region, err = cardinfo.ParseRegion(*region); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		exit(2)
+	}
+	if uiOpts.NameLang, err = api.ParseNameLang(*nameLang); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		exit(2)
+	}
+	
+	// Commands print text unless a format is given; exports default to JSON
+	var output *format.Format
+	if *outputFormat != "" {
+		if output, err = format.Lookup(*outputFormat); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			flag.Usage()
+			exit(2)
+		}
+		uiOpts.ExportFormat = output
+	} else {
+		uiOpts.ExportFormat = format.Formats()[0]
+	}
+	
+	if *datasetDir == "" {
+		if *datasetDir, err = dataset.Dir(); err != nil {
+			log.Warn("synced dataset unavailable: %v", err)
+		}
+	}
+	
+	if uiOpts.DeckDir, err = deck.Dir(); err != nil {
+		log.Warn("saving decks disabled: %v", err)
+	}
+	if dataDir, err := paths.DataDir(); err != nil {
+		log.Warn("exporting disabled: %v", err)
+	} else {
+		uiOpts.ExportDir = filepath.Join(dataDir, "exports")
+	}
+	if path, err := favorites.Path(); err != nil {
+		log.Warn("favorites disabled: %v", err)
+	} else if uiOpts.Favorites, err = favorites.Load(path); err != nil {
+		log.Error("failed to load favorites: %v", err)
+		stdlog.Fatal(err)
+	}
+	if path, err := history.Path(); err != nil {
+		log.Warn("search history disabled: %v", err)
+	} else if uiOpts.History, err = history.Load(path); err != nil {
+		log.Error("failed to load search history: %v", err)
+		stdlog.Fatal(err)
+	}
+	
+	// Load archetype and counter names
+	if len(stringsPaths) > 0 {
+		names, err := cardinfo.LoadStrings(stringsPaths...)
+		if err != nil {
+			log.Error("failed to load strings.conf: %v", err)
+			stdlog.Fatal(err)
+		}
+		names.Use()
+	}
+	
+	// Load Forbidden/Limited lists
+	if len(lflistPaths) > 0 {
+		if uiOpts.Banlists, err = banlist.Load(lflistPaths...); err != nil {
+			log.Error("failed to load lflist.conf: %v", err)
+			stdlog.Fatal(err)
+		}
+		log.Info("Loaded %d banlists", len(uiOpts.Banlists))
+	}
+	if *banlistName != "" {
+		if uiOpts.Banlist, err = banlist.Find(uiOpts.Banlists, *banlistName); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			flag.Usage()
+			exit(2)
+		}
+	} else if len(uiOpts.Banlists) > 0 {
+		uiOpts.Banlist = uiOpts.Banlists[0]
+	}
+	
+	// Create the API client
+	opts := []api.Option{
+		api.WithBaseURL(*baseURL),
+		api.WithTimeout(*timeout),
+		api.WithRetry(*retries, api.DefaultRetryDelay),
+	}
+	if !*noCache {
+		if cache, err := newCache(*cacheTTL); err != nil {
+			log.Warn("response cache disabled: %v", err)
+		} else {
+			opts = append(opts, api.WithCache(cache))
+		}
+	}
+	client := api.NewClient(opts...)
+	
+	// Run the subcommand, if any
+	switch command := flag.Arg(0); command {
+	case "":
+	case "sync":
+		exit(runSync(client, *datasetDir, flag.Args()[1:]))
+	case "search", "show", "lookup", "ydk", "validate":
+		source, err := newSource(client, cdbPaths, *datasetDir, *online)
+		if err != nil {
+			log.Error("failed to load local cards: %v", err)
+			stdlog.Fatal(err)
+		}
+		args := flag.Args()[1:]
+		switch command {
+		case "search":
+			exit(runSearch(source, uiOpts, output, args))
+		case "show":
+			exit(runShow(source, uiOpts, output, args))
+		case "lookup":
+			exit(runLookup(source, uiOpts, output, args))
+		case "ydk":
+			exit(runYDK(source, uiOpts.NameLang, args))
+		default:
+			exit(runValidate(source, deck.Rules{
+				Banlist:  uiOpts.Banlist,
+				Region:   uiOpts.Region,
+				NameLang: uiOpts.NameLang,
+			}, args))
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command: %s\n\n", command)
+		flag.Usage()
+		exit(2)
+	}
+	
+	// Choose where cards are served from
+	source, err := newSource(client, cdbPaths, *datasetDir, *online)
+	if err != nil {
+		log.Error("failed to load local cards: %v", err)
+		stdlog.Fatal(err)
+	}
+	
 	// Start the TUI application
-	if err := ui.Start(); err != nil {
+	if err := ui.Start(source, uiOpts); err != nil {
 		if logLevelFlag.set {
 			log.Error("application error: %v", err)
 			log.Close()
@@ -80,4 +289,49 @@ func main() {
 	if logLevelFlag.set {
 		log.Info("ygocdb-tui exited normally")
 	}
-}
\ No newline at end of file
+}
+
+// newCache creates the API response cache in the user cache directory
+func newCache(ttl time.Duration) (*api.Cache, error) {
+	dir, err := paths.CacheDir()
+	if err != nil {
+		return nil, err
+	}
+	return api.NewCache(filepath.Join(dir, "http"), ttl)
+}
+
+// newSource returns the card source to use: the given card databases, the
+// dataset synced to datasetDir if present, or the API client
+func newSource(client *api.Client, cdbPaths []string, datasetDir string, online bool) (api.CardSource, error) {
+	// Serve cards offline from card databases if requested
+	if len(cdbPaths) > 0 {
+		cards, aliases, err := cdb.Load(cdbPaths...)
+		if err != nil {
+			return nil, err
+		}
+		return local.NewStore(cards, aliases), nil
+	}
+	
+	if online {
+		return client, nil
+	}
+	
+	// Serve cards from the synced dataset if present
+	if datasetDir == "" {
+		return client, nil
+	}
+	cards, _, err := dataset.Load(datasetDir)
+	if err != nil {
+		if !errors.Is(err, dataset.ErrNotSynced) {
+			log.Warn("synced dataset unavailable: %v", err)
+		}
+		return client, nil
+	}
+	return local.NewStore(cards, nil), nil
+}
+
+// exit closes the logger and exits with the given status code
+func exit(code int) {
+	log.Close()
+	os.Exit(code)
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/dataset"
+	"ygocdb-tui/internal/log"
+)
+
+// runSync runs the sync command, downloading the dataset to dir, and returns
+// the exit status
+func runSync(client *api.Client, dir string, args []string) int {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	force := fs.Bool("force", false, "download the dataset even if it is up to date")
+	timeout := fs.Duration("timeout", 10*time.Minute, "timeout of the whole download")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s sync [options]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Download the full ygocdb card dataset for local search.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if dir == "" {
+		fmt.Fprintf(os.Stderr, "错误: 未找到数据目录，请使用 -dataset-dir 指定数据集目录\n")
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *timeout)
+	defer cancel()
+
+	fmt.Printf("正在同步卡片数据到 %s ...\n", dir)
+	meta, updated, err := dataset.Sync(ctx, client, dir, *force)
+	if err != nil {
+		log.Error("sync failed: %v", err)
+		fmt.Fprintf(os.Stderr, "同步失败: %v\n", err)
+		return 1
+	}
+
+	if updated {
+		fmt.Printf("同步完成: 共 %d 张卡片，版本 %s\n", meta.Count, meta.Version)
+	} else {
+		fmt.Printf("卡片数据已是最新: 共 %d 张卡片，版本 %s，同步于 %s\n",
+			meta.Count, meta.Version, meta.SyncedAt.Format("2006-01-02 15:04:05"))
+	}
+	return 0
+}
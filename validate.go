@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/deck"
+	"ygocdb-tui/internal/log"
+)
+
+// runValidate runs the validate command, reporting the rules each deck
+// violates, and returns the exit status: 0 if every deck is legal
+func runValidate(source api.CardSource, rules deck.Rules, args []string) int {
+	fs := flag.NewFlagSet("validate", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s [options] validate <file.ydk|deck.json|ydke://...>...\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Check decks against the active banlist (-lflist, -banlist) and region (-region).\n")
+		fmt.Fprintf(os.Stderr, "Exits with status 1 if any deck is not legal.\n")
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if rules.Banlist != nil {
+		fmt.Printf("禁限卡表: %s，地区: %s\n\n", rules.Banlist.Name, rules.Region)
+	} else {
+		fmt.Printf("禁限卡表: 无，地区: %s\n\n", rules.Region)
+	}
+
+	status := 0
+	for _, path := range fs.Args() {
+		// Cards that are not found are reported as violations
+		d, err := loadDeck(ctx, source, path)
+		var missing *deck.MissingError
+		if err != nil && !errors.As(err, &missing) {
+			log.Error("failed to load deck %s: %v", path, err)
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			status = 1
+			continue
+		}
+
+		violations := deck.Validate(d, rules)
+		if len(violations) == 0 {
+			fmt.Printf("%s: 合法\n", d.Name)
+			continue
+		}
+		status = 1
+		fmt.Printf("%s: %d 个问题\n", d.Name, len(violations))
+		for _, violation := range violations {
+			fmt.Printf("  - %s\n", violation.Message)
+		}
+	}
+	return status
+}
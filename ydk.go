@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"ygocdb-tui/internal/api"
+	"ygocdb-tui/internal/deck"
+	"ygocdb-tui/internal/log"
+)
+
+// runYDK runs the ydk command, printing .ydk deck files and ydke:// links
+// as card lists, and returns the exit status
+func runYDK(source api.CardSource, lang api.NameLang, args []string) int {
+	fs := flag.NewFlagSet("ydk", flag.ExitOnError)
+	link := fs.Bool("ydke", false, "print the decks as ydke:// links instead of card lists")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s ydk [options] <file.ydk|deck.json|ydke://...>...\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "Print YGOPro .ydk deck files and ydke:// links as lists of card names.\n\n")
+		fmt.Fprintf(os.Stderr, "Options:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	status := 0
+	for i, path := range fs.Args() {
+		if i > 0 && !*link {
+			fmt.Println()
+		}
+		if err := printYDK(ctx, source, lang, path, *link); err != nil {
+			log.Error("failed to print deck %s: %v", path, err)
+			fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+			status = 1
+		}
+	}
+	return status
+}
+
+// printYDK prints the cards of a deck by zone, or the deck as a ydke:// link
+func printYDK(ctx context.Context, source api.CardSource, lang api.NameLang, path string, link bool) error {
+	if link {
+		passcodes, _, err := readPasscodes(path)
+		if err != nil {
+			return err
+		}
+		fmt.Println(passcodes.YDKE())
+		return nil
+	}
+	
+	d, err := loadDeck(ctx, source, path)
+	var missing *deck.MissingError
+	if err != nil && !errors.As(err, &missing) {
+		return err
+	}
+
+	fmt.Println(d.Name)
+	for _, zone := range deck.Zones {
+		fmt.Printf("%s (%d 张)\n", zone, len(d.Cards(zone)))
+		for _, entry := range d.Entries(zone) {
+			cardName := entry.Card.DisplayName(lang)
+			if cardName == "" {
+				cardName = "未知卡片"
+			}
+			fmt.Printf("  %d× %s (%d)\n", entry.Count, cardName, entry.Card.ID)
+		}
+	}
+	return err
+}
+
+// loadDeck loads a deck from a ydke:// link, a saved .json deck or a .ydk
+// deck file, looking up the cards of links and .ydk files in source. Cards
+// that are not found are kept with only their passcode and reported by a
+// *deck.MissingError along with the deck.
+func loadDeck(ctx context.Context, source api.CardSource, path string) (*deck.Deck, error) {
+	if isDeckFile(path) {
+		return deck.ReadFile(path)
+	}
+	passcodes, name, err := readPasscodes(path)
+	if err != nil {
+		return nil, err
+	}
+	return deck.Resolve(ctx, source, name, passcodes)
+}
+
+// readPasscodes reads the passcodes of a deck from a ydke:// link, a saved
+// .json deck or a .ydk deck file, and returns them with the deck name
+func readPasscodes(path string) (*deck.Passcodes, string, error) {
+	switch {
+	case deck.IsYDKE(path):
+		passcodes, err := deck.ParseYDKE(path)
+		return passcodes, "ydke", err
+	case isDeckFile(path):
+		d, err := deck.ReadFile(path)
+		if err != nil {
+			return nil, "", err
+		}
+		return d.Passcodes(), d.Name, nil
+	default:
+		passcodes, err := deck.ReadYDKFile(path)
+		return passcodes, strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), err
+	}
+}
+
+// isDeckFile reports whether path is a deck saved by the deck builder
+func isDeckFile(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".json")
+}